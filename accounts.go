@@ -0,0 +1,348 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// AccountType is the type of an account in the chart of accounts. Only
+// profit and loss and status (balance sheet) accounts can be posted to; the
+// others structure the chart and sum other accounts.
+type AccountType string
+
+const (
+	AccountTypeProfitAndLoss AccountType = "profitAndLoss"
+	AccountTypeStatus        AccountType = "status"
+	AccountTypeTotalFrom     AccountType = "totalFrom"
+	AccountTypeHeading       AccountType = "heading"
+	AccountTypeHeadingStart  AccountType = "headingStart"
+	AccountTypeSumInterval   AccountType = "sumInterval"
+	AccountTypeSumAlpha      AccountType = "sumAlpha"
+)
+
+// AccountTypes are all account types.
+var AccountTypes = []AccountType{
+	AccountTypeProfitAndLoss, AccountTypeStatus, AccountTypeTotalFrom, AccountTypeHeading,
+	AccountTypeHeadingStart, AccountTypeSumInterval, AccountTypeSumAlpha,
+}
+
+// IsPosting reports whether entries can be posted to accounts of type t.
+func (t AccountType) IsPosting() bool {
+	return t == AccountTypeProfitAndLoss || t == AccountTypeStatus
+}
+
+// Account is an account in the chart of accounts.
+type Account struct {
+	AccountNumber      int               `json:"accountNumber"`
+	AccountType        AccountType       `json:"accountType"`
+	Name               string            `json:"name"`
+	Balance            Amount            `json:"balance"`                      // The current balance in base currency.
+	Barred             bool              `json:"barred,omitempty"`             // A barred account cannot be posted to.
+	BlockDirectEntries bool              `json:"blockDirectEntries,omitempty"` // The account only takes entries made through e.g. invoices, not journal entries.
+	DebitCredit        string            `json:"debitCredit,omitempty"`        // "debit" or "credit", the side the account normally has its balance on.
+	VatAccount         *VatAccount       `json:"vatAccount,omitempty"`         // The default VAT code of entries to the account.
+	ContraAccount      *AccountReference `json:"contraAccount,omitempty"`      // The default contra account of entries to the account.
+	TotalFromAccount   *AccountReference `json:"totalFromAccount,omitempty"`   // For totalFrom accounts: the first account in the total.
+	AccountsSummed     []AccountInterval `json:"accountsSummed,omitempty"`     // For sum accounts: the intervals of accounts summed.
+	Self               string            `json:"self,omitempty"`
+}
+
+type AccountReference struct {
+	AccountNumber int    `json:"accountNumber"`
+	Self          string `json:"self,omitempty"`
+}
+
+type AccountInterval struct {
+	FromAccount AccountReference `json:"fromAccount"`
+	ToAccount   AccountReference `json:"toAccount"`
+}
+
+type VatAccount struct {
+	VatCode string `json:"vatCode"`
+	Self    string `json:"self,omitempty"`
+}
+
+// AccountingYear is a financial year of the agreement. Years that do not
+// follow the calendar are named after both calendar years, e.g. "2023/2024".
+type AccountingYear struct {
+	Year     string `json:"year"`
+	FromDate Date   `json:"fromDate"`
+	ToDate   Date   `json:"toDate"`
+	Closed   bool   `json:"closed"`
+	Self     string `json:"self,omitempty"`
+}
+
+// Contains reports whether date falls within the year.
+func (y AccountingYear) Contains(date Date) bool {
+	return !date.Before(y.FromDate) && !date.After(y.ToDate)
+}
+
+type AccountingPeriod struct {
+	PeriodNumber int    `json:"periodNumber"`
+	FromDate     Date   `json:"fromDate"`
+	ToDate       Date   `json:"toDate"`
+	Barred       bool   `json:"barred"` // No entries can be posted in a barred period.
+	Self         string `json:"self,omitempty"`
+}
+
+// AccountEntry is a booked entry on an account.
+type AccountEntry struct {
+	EntryNumber          int               `json:"entryNumber"`
+	EntryType            string            `json:"entryType"`
+	Date                 Date              `json:"date"`
+	Amount               Amount            `json:"amount"` // In Currency; positive for debit, negative for credit.
+	AmountInBaseCurrency Amount            `json:"amountInBaseCurrency"`
+	Currency             string            `json:"currency"`
+	Text                 string            `json:"text,omitempty"`
+	VoucherNumber        int               `json:"voucherNumber"`
+	Account              *AccountReference `json:"account,omitempty"`
+	Self                 string            `json:"self,omitempty"`
+}
+
+// accountingYearPath is the path segment of year; a year like "2023/2024"
+// is addressed as "2023_2024".
+func accountingYearPath(year string) string {
+	return strings.ReplaceAll(year, "/", "_")
+}
+
+func (client *Client) GetAccounts() ([]Account, error) {
+	return client.GetAccountsContext(context.Background())
+}
+
+func (client *Client) GetAccountsContext(ctx context.Context) ([]Account, error) {
+	tc := &TypedClient[Account]{client: client}
+	return tc.getEntities(ctx, "accounts", ListOptions{PageSize: MAX_PAGE_SIZE})
+}
+
+func (client *Client) GetAccount(accountNumber int) (*Account, error) {
+	return client.GetAccountContext(context.Background(), accountNumber)
+}
+
+func (client *Client) GetAccountContext(ctx context.Context, accountNumber int) (*Account, error) {
+	var account Account
+	err := client.callRestAPI(ctx, fmt.Sprintf("accounts/%d", accountNumber), http.MethodGet, nil, &account)
+	return &account, err
+}
+
+func (client *Client) GetAccountingYears() ([]AccountingYear, error) {
+	return client.GetAccountingYearsContext(context.Background())
+}
+
+func (client *Client) GetAccountingYearsContext(ctx context.Context) ([]AccountingYear, error) {
+	tc := &TypedClient[AccountingYear]{client: client}
+	return tc.getEntities(ctx, "accounting-years", ListOptions{})
+}
+
+func (client *Client) GetAccountingYear(year string) (*AccountingYear, error) {
+	return client.GetAccountingYearContext(context.Background(), year)
+}
+
+func (client *Client) GetAccountingYearContext(ctx context.Context, year string) (*AccountingYear, error) {
+	var y AccountingYear
+	err := client.callRestAPI(ctx, "accounting-years/"+accountingYearPath(year), http.MethodGet, nil, &y)
+	return &y, err
+}
+
+func (client *Client) GetAccountingPeriods(year string) ([]AccountingPeriod, error) {
+	return client.GetAccountingPeriodsContext(context.Background(), year)
+}
+
+func (client *Client) GetAccountingPeriodsContext(ctx context.Context, year string) ([]AccountingPeriod, error) {
+	tc := &TypedClient[AccountingPeriod]{client: client}
+	return tc.getEntities(ctx, "accounting-years/"+accountingYearPath(year)+"/periods", ListOptions{})
+}
+
+// AccountEntriesPager pages through the booked entries of an account in an
+// accounting year.
+func (client *Client) AccountEntriesPager(accountNumber int, year string, opts ListOptions) *Pager[AccountEntry] {
+	tc := &TypedClient[AccountEntry]{client: client}
+	return tc.pager(fmt.Sprintf("accounts/%d/accounting-years/%s/entries", accountNumber, accountingYearPath(year)), opts)
+}
+
+func (client *Client) GetAccountEntries(accountNumber int, year string) ([]AccountEntry, error) {
+	return client.GetAccountEntriesContext(context.Background(), accountNumber, year)
+}
+
+func (client *Client) GetAccountEntriesContext(ctx context.Context, accountNumber int, year string) ([]AccountEntry, error) {
+	return client.AccountEntriesPager(accountNumber, year, ListOptions{PageSize: MAX_PAGE_SIZE}).Collect(ctx)
+}
+
+// Ledger is the general ledger of one account for an accounting year.
+type Ledger struct {
+	Account Account
+	Year    string
+	Lines   []LedgerLine // The entries by date and entry number.
+	Total   Amount       // The sum of the entries in base currency.
+}
+
+type LedgerLine struct {
+	AccountEntry
+	Balance Amount // The running total in base currency, this entry included.
+}
+
+func (client *Client) GetLedger(accountNumber int, year string) (*Ledger, error) {
+	return client.GetLedgerContext(context.Background(), accountNumber, year)
+}
+
+// GetLedgerContext returns the account's entries in year with running
+// totals. The totals start from zero, not from the balance carried forward
+// from earlier years.
+func (client *Client) GetLedgerContext(ctx context.Context, accountNumber int, year string) (*Ledger, error) {
+	account, err := client.GetAccountContext(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.GetAccountEntriesContext(ctx, accountNumber, year)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if c := entries[i].Date.Compare(entries[j].Date); c != 0 {
+			return c < 0
+		}
+		return entries[i].EntryNumber < entries[j].EntryNumber
+	})
+	ledger := &Ledger{Account: *account, Year: year, Lines: make([]LedgerLine, len(entries))}
+	for i, entry := range entries {
+		ledger.Total = ledger.Total.Add(entry.AmountInBaseCurrency)
+		ledger.Lines[i] = LedgerLine{AccountEntry: entry, Balance: ledger.Total}
+	}
+	return ledger, nil
+}
+
+// ChartOfAccounts is the chart of accounts with the accounting years and the
+// periods of the open years, for checking postings before they are made.
+type ChartOfAccounts struct {
+	Accounts []Account
+	Years    []AccountingYear
+	Periods  map[string][]AccountingPeriod // By year, for the years that are not closed.
+}
+
+func (client *Client) GetChartOfAccounts() (*ChartOfAccounts, error) {
+	return client.GetChartOfAccountsContext(context.Background())
+}
+
+func (client *Client) GetChartOfAccountsContext(ctx context.Context) (*ChartOfAccounts, error) {
+	accounts, err := client.GetAccountsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	years, err := client.GetAccountingYearsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chart := &ChartOfAccounts{Accounts: accounts, Years: years, Periods: map[string][]AccountingPeriod{}}
+	for _, year := range years {
+		if year.Closed {
+			continue
+		}
+		if chart.Periods[year.Year], err = client.GetAccountingPeriodsContext(ctx, year.Year); err != nil {
+			return nil, err
+		}
+	}
+	return chart, nil
+}
+
+func (c *ChartOfAccounts) Account(accountNumber int) (Account, bool) {
+	return find(c.Accounts, func(a Account) bool { return a.AccountNumber == accountNumber })
+}
+
+// AccountByName finds an account by its name, ignoring case.
+func (c *ChartOfAccounts) AccountByName(name string) (Account, bool) {
+	return find(c.Accounts, func(a Account) bool { return strings.EqualFold(a.Name, name) })
+}
+
+// AccountingYear returns the accounting year date falls in.
+func (c *ChartOfAccounts) AccountingYear(date Date) (AccountingYear, bool) {
+	return find(c.Years, func(y AccountingYear) bool { return y.Contains(date) })
+}
+
+// checkPostingAccount returns why entries cannot be posted to accountNumber,
+// or nil.
+func (c *ChartOfAccounts) checkPostingAccount(accountNumber int) error {
+	account, ok := c.Account(accountNumber)
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown account %d", accountNumber)
+	case !account.AccountType.IsPosting():
+		return fmt.Errorf("account %d (%s) is of type %s and cannot be posted to", accountNumber, account.Name, account.AccountType)
+	case account.Barred:
+		return fmt.Errorf("account %d (%s) is barred", accountNumber, account.Name)
+	case account.BlockDirectEntries:
+		return fmt.Errorf("account %d (%s) blocks direct entries", accountNumber, account.Name)
+	}
+	return nil
+}
+
+// checkDate returns why nothing can be posted on date, or nil.
+func (c *ChartOfAccounts) checkDate(date Date) error {
+	year, ok := c.AccountingYear(date)
+	switch {
+	case !ok:
+		return fmt.Errorf("no accounting year contains %s", date)
+	case year.Closed:
+		return fmt.Errorf("accounting year %s of %s is closed", year.Year, date)
+	}
+	for _, period := range c.Periods[year.Year] {
+		if !date.Before(period.FromDate) && !date.After(period.ToDate) && period.Barred {
+			return fmt.Errorf("accounting period %d of %s is barred", period.PeriodNumber, date)
+		}
+	}
+	return nil
+}
+
+// ValidateJournalEntry checks that the accounts of entry can be posted to and
+// that its date is in an open accounting year and period. Entries for a
+// customer, such as customer payments, need no account.
+func (c *ChartOfAccounts) ValidateJournalEntry(entry *JournalEntry) error {
+	var errs []error
+	if entry.AccountNumber != 0 || entry.CustomerNumber == 0 {
+		if err := c.checkPostingAccount(entry.AccountNumber); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if entry.ContraAccountNumber != 0 {
+		if err := c.checkPostingAccount(entry.ContraAccountNumber); err != nil {
+			errs = append(errs, fmt.Errorf("contra %w", err))
+		}
+	}
+	if err := c.checkDate(entry.Date); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateVoucher validates the entries of v (see ValidateJournalEntry).
+func (c *ChartOfAccounts) ValidateVoucher(v *Voucher) error {
+	var errs []error
+	for i, entry := range v.Entries() {
+		if err := c.ValidateJournalEntry(&entry); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (client *Client) ValidateJournalEntries(entries ...JournalEntry) error {
+	return client.ValidateJournalEntriesContext(context.Background(), entries...)
+}
+
+// ValidateJournalEntriesContext loads the chart of accounts and validates
+// the entries against it (see ChartOfAccounts.ValidateJournalEntry).
+func (client *Client) ValidateJournalEntriesContext(ctx context.Context, entries ...JournalEntry) error {
+	chart, err := client.GetChartOfAccountsContext(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range entries {
+		if err := chart.ValidateJournalEntry(&entries[i]); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+package economic
+
+import (
+	"testing"
+
+	"github.com/Opus-EDB/e-conomic/econtest"
+)
+
+func seedTestAccounts(srv *econtest.Server) {
+	srv.Add("accounts",
+		Account{AccountNumber: 1010, AccountType: AccountTypeProfitAndLoss, Name: "Salg"},
+		Account{AccountNumber: 1000, AccountType: AccountTypeHeading, Name: "Omsætning"},
+		Account{AccountNumber: 5600, AccountType: AccountTypeStatus, Name: "Debitorer", BlockDirectEntries: true},
+		Account{AccountNumber: 5820, AccountType: AccountTypeStatus, Name: "Bank"},
+		Account{AccountNumber: 7220, AccountType: AccountTypeProfitAndLoss, Name: "Gebyrer", Barred: true},
+	)
+	srv.Add("accounting-years",
+		AccountingYear{Year: "2023", FromDate: NewDate(2023, 1, 1), ToDate: NewDate(2023, 12, 31), Closed: true},
+		AccountingYear{Year: "2024", FromDate: NewDate(2024, 1, 1), ToDate: NewDate(2024, 12, 31)},
+	)
+	srv.Add("accounting-years/2024/periods",
+		AccountingPeriod{PeriodNumber: 1, FromDate: NewDate(2024, 1, 1), ToDate: NewDate(2024, 1, 31), Barred: true},
+		AccountingPeriod{PeriodNumber: 2, FromDate: NewDate(2024, 2, 1), ToDate: NewDate(2024, 12, 31)},
+	)
+}
+
+func TestValidateJournalEntries(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	seedTestAccounts(srv)
+	chart, err := client.GetChartOfAccounts()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(chart.Accounts) != 5 || len(chart.Years) != 2 || len(chart.Periods["2024"]) != 2 || chart.Periods["2023"] != nil {
+		t.Fatalf("Unexpected chart of accounts %+v", chart)
+	}
+	if account, ok := chart.AccountByName("bank"); !ok || account.AccountNumber != 5820 {
+		t.Fatalf("Expected the bank account, got %+v", account)
+	}
+
+	valid := JournalEntry{AccountNumber: 5820, ContraAccountNumber: 1010, Date: NewDate(2024, 3, 1), Amount: MustParseAmount("100")}
+	if err := chart.ValidateJournalEntry(&valid); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	payment := JournalEntry{CustomerNumber: 1, ContraAccountNumber: 5820, Date: NewDate(2024, 3, 1)}
+	if err := chart.ValidateJournalEntry(&payment); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	invalid := []JournalEntry{
+		{AccountNumber: 9999, Date: NewDate(2024, 3, 1)},
+		{AccountNumber: 1000, Date: NewDate(2024, 3, 1)},
+		{AccountNumber: 5600, Date: NewDate(2024, 3, 1)},
+		{AccountNumber: 5820, ContraAccountNumber: 7220, Date: NewDate(2024, 3, 1)},
+		{AccountNumber: 5820, Date: NewDate(2023, 6, 1)},
+		{AccountNumber: 5820, Date: NewDate(2024, 1, 15)},
+		{AccountNumber: 5820, Date: NewDate(2025, 1, 1)},
+	}
+	for i := range invalid {
+		if err := chart.ValidateJournalEntry(&invalid[i]); err == nil {
+			t.Fatalf("Expected entry %d to be invalid: %+v", i, invalid[i])
+		}
+	}
+	if err := client.ValidateJournalEntries(valid, invalid[0]); err == nil {
+		t.Fatalf("Expected an error for the unknown account")
+	}
+
+	v := NewVoucher(1, 0, NewDate(2024, 3, 1), "DKK").
+		Add(VoucherLine{AccountNumber: 5820, Amount: MustParseAmount("100")}).
+		Add(VoucherLine{AccountNumber: 7220, Amount: MustParseAmount("-100")})
+	if err := chart.ValidateVoucher(v); err == nil {
+		t.Fatalf("Expected the barred account to be rejected")
+	}
+}
+
+func TestGetLedger(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	seedTestAccounts(srv)
+	srv.Add("accounts/5820/accounting-years/2024/entries",
+		AccountEntry{EntryNumber: 3, Date: NewDate(2024, 3, 5), Amount: MustParseAmount("-25"), AmountInBaseCurrency: MustParseAmount("-25"), Currency: "DKK"},
+		AccountEntry{EntryNumber: 1, Date: NewDate(2024, 3, 1), Amount: MustParseAmount("1250"), AmountInBaseCurrency: MustParseAmount("1250"), Currency: "DKK"},
+		AccountEntry{EntryNumber: 2, Date: NewDate(2024, 3, 1), Amount: MustParseAmount("-10"), AmountInBaseCurrency: MustParseAmount("-74.5"), Currency: "EUR"},
+	)
+	ledger, err := client.GetLedger(5820, "2024")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if ledger.Account.Name != "Bank" || len(ledger.Lines) != 3 || ledger.Total != MustParseAmount("1150.5") {
+		t.Fatalf("Unexpected ledger %+v", ledger)
+	}
+	for i, balance := range []string{"1250", "1175.5", "1150.5"} {
+		if line := ledger.Lines[i]; line.EntryNumber != i+1 || line.Balance != MustParseAmount(balance) {
+			t.Fatalf("Line %d: expected entry %d with balance %s, got %+v", i, i+1, balance, line)
+		}
+	}
+	if _, err := client.GetLedger(4242, "2024"); !IsNotFound(err) {
+		t.Fatalf("Expected not found, got %v", err)
+	}
+}
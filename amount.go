@@ -0,0 +1,276 @@
+package economic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// amountDecimals is the precision of an Amount. It is finer than any
+// currency so that unit prices and intermediate results stay exact; round
+// with Round before comparing with what e-conomic books.
+const amountDecimals = 6
+
+var amountScale = big.NewInt(1_000_000)
+
+// Amount is an exact decimal amount of money with six decimals. The zero
+// value is 0. It marshals to and from a JSON number, and also accepts the
+// quoted numbers some endpoints return.
+//
+//	price, _ := economic.ParseAmount("99.95")
+//	total := price.Mul(3).Round("DKK") // 299.85
+type Amount struct {
+	micros int64
+}
+
+// ParseAmount parses a decimal number such as "-1234.5" or "1e3". It fails
+// on more than six decimals rather than rounding silently.
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	// big.Rat also accepts fractions and hex; only allow decimal numbers
+	notDecimal := func(r rune) bool { return !strings.ContainsRune("0123456789.+-eE", r) }
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.IndexFunc(s, notDecimal) >= 0 {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(amountScale))
+	if !r.IsInt() {
+		return Amount{}, fmt.Errorf("amount %q has more than %d decimals", s, amountDecimals)
+	}
+	if !r.Num().IsInt64() {
+		return Amount{}, fmt.Errorf("amount %q is out of range", s)
+	}
+	return Amount{micros: r.Num().Int64()}, nil
+}
+
+// MustParseAmount is ParseAmount for constants; it panics on an error.
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewAmount converts a float64, e.g. from another system, to the Amount
+// with the same shortest decimal representation, rounded to six decimals.
+// NaN and ±Inf give zero; it panics if f is too large for an Amount (see
+// NewAmountChecked).
+func NewAmount(f float64) Amount {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Amount{}
+	}
+	a, err := NewAmountChecked(f)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewAmountChecked is NewAmount returning an error for NaN, ±Inf and
+// values beyond the range of an Amount, about ±9.2e12.
+func NewAmountChecked(f float64) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok { // NaN or ±Inf
+		return Amount{}, fmt.Errorf("invalid amount %v", f)
+	}
+	return ratToAmount(r)
+}
+
+// AmountFromMinor returns the amount of minor units (øre, cents) of a
+// currency, e.g. AmountFromMinor(12345, "DKK") is 123.45.
+func AmountFromMinor(minor int64, currency string) Amount {
+	return Amount{micros: minor * pow10(amountDecimals-currencyDecimals(currency))}
+}
+
+// ratToAmount rounds r to six decimals, failing if it is out of range.
+func ratToAmount(r *big.Rat) (Amount, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(amountScale))
+	q, m := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	// round half away from zero
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(scaled.Num().Sign())))
+	}
+	if !q.IsInt64() {
+		return Amount{}, fmt.Errorf("amount %s is out of range", r.FloatString(amountDecimals))
+	}
+	return Amount{micros: q.Int64()}, nil
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+func (a Amount) Add(b Amount) Amount { return Amount{micros: a.micros + b.micros} }
+func (a Amount) Sub(b Amount) Amount { return Amount{micros: a.micros - b.micros} }
+func (a Amount) Neg() Amount         { return Amount{micros: -a.micros} }
+func (a Amount) IsZero() bool        { return a.micros == 0 }
+
+// Sign returns -1, 0 or 1.
+func (a Amount) Sign() int {
+	switch {
+	case a.micros < 0:
+		return -1
+	case a.micros > 0:
+		return 1
+	}
+	return 0
+}
+
+func (a Amount) Abs() Amount {
+	if a.micros < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+// Cmp returns -1, 0 or 1 as a is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	return a.Sub(b).Sign()
+}
+
+// Mul multiplies by a quantity or factor, e.g. a line's quantity or
+// 1-discount/100. The factor is taken at its shortest decimal representation
+// and the result is rounded to six decimals. A NaN or ±Inf factor gives
+// zero; it panics if the result is too large for an Amount (see MulChecked).
+func (a Amount) Mul(factor float64) Amount {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		return Amount{}
+	}
+	product, err := a.MulChecked(factor)
+	if err != nil {
+		panic(err)
+	}
+	return product
+}
+
+// MulChecked is Mul returning an error for a NaN or ±Inf factor and for
+// results beyond the range of an Amount.
+func (a Amount) MulChecked(factor float64) (Amount, error) {
+	f, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+	if !ok { // NaN or ±Inf
+		return Amount{}, fmt.Errorf("invalid factor %v", factor)
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(a.micros), amountScale)
+	return ratToAmount(r.Mul(r, f))
+}
+
+// Round rounds half away from zero to the minor unit of currency: two
+// decimals for most currencies, none for e.g. JPY and ISK, three for e.g.
+// KWD. An unknown or empty currency rounds to two decimals.
+func (a Amount) Round(currency string) Amount {
+	return a.RoundTo(currencyDecimals(currency))
+}
+
+// RoundTo rounds half away from zero to the given number of decimals.
+func (a Amount) RoundTo(decimals int) Amount {
+	if decimals >= amountDecimals {
+		return a
+	}
+	unit := pow10(amountDecimals - max(decimals, 0))
+	q, rem := a.micros/unit, a.micros%unit
+	if 2*abs64(rem) >= unit {
+		if a.micros < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount{micros: q * unit}
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Minor returns the amount in minor units of currency, rounding as Round.
+func (a Amount) Minor(currency string) int64 {
+	return a.Round(currency).micros / pow10(amountDecimals-currencyDecimals(currency))
+}
+
+// Float64 returns the nearest float64, for display or interop only.
+func (a Amount) Float64() float64 {
+	return float64(a.micros) / math.Pow10(amountDecimals)
+}
+
+// String formats the amount with as few decimals as needed, e.g. "-12.5".
+func (a Amount) String() string {
+	s := a.StringFixed(amountDecimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// StringFixed formats the amount rounded to exactly decimals decimals, e.g.
+// "1234.50" for StringFixed(2).
+func (a Amount) StringFixed(decimals int) string {
+	decimals = min(max(decimals, 0), amountDecimals)
+	r := a.RoundTo(decimals)
+	sign := ""
+	if r.micros < 0 {
+		sign = "-"
+	}
+	units := abs64(r.micros) / pow10(amountDecimals)
+	frac := abs64(r.micros) % pow10(amountDecimals)
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, units)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, units, decimals, frac/pow10(amountDecimals-decimals))
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*a = Amount{}
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*a = Amount{}
+			return nil
+		}
+	}
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Ptr returns a pointer to a copy of a, for the optional amounts on Order and
+// OrderLine.
+func (a Amount) Ptr() *Amount {
+	return &a
+}
+
+// currencyDecimals is the number of decimals of the minor unit of an ISO
+// 4217 currency.
+func currencyDecimals(currency string) int {
+	switch strings.ToUpper(currency) {
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG", "RWF", "UGX", "UYI", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3
+	}
+	return 2
+}
@@ -0,0 +1,129 @@
+package economic
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"100", "100"},
+		{"-1234.50", "-1234.5"},
+		{"0.1", "0.1"},
+		{"1e3", "1000"},
+		{" 99.95 ", "99.95"},
+		{"0.000001", "0.000001"},
+	}
+	for _, test := range tests {
+		a, err := ParseAmount(test.in)
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if a.String() != test.want {
+			t.Errorf("ParseAmount(%q) = %s, expected %s", test.in, a, test.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1/3", "0x10", "0.0000001", "1,5"} {
+		if _, err := ParseAmount(in); err == nil {
+			t.Errorf("Expected ParseAmount(%q) to fail", in)
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exactly 0.3, unlike with float64
+	if sum := MustParseAmount("0.1").Add(MustParseAmount("0.2")); sum != MustParseAmount("0.3") {
+		t.Fatalf("Expected 0.3, got %s", sum)
+	}
+	if total := MustParseAmount("99.95").Mul(3); total != MustParseAmount("299.85") {
+		t.Fatalf("Expected 299.85, got %s", total)
+	}
+	if discounted := MustParseAmount("100").Mul(1 - 12.5/100); discounted != MustParseAmount("87.5") {
+		t.Fatalf("Expected 87.5, got %s", discounted)
+	}
+	if NewAmount(0.1) != MustParseAmount("0.1") {
+		t.Fatalf("Expected NewAmount(0.1) to be 0.1, got %s", NewAmount(0.1))
+	}
+	if a := MustParseAmount("-5").Sub(MustParseAmount("2.5")); a.Sign() != -1 || a.Abs() != MustParseAmount("7.5") {
+		t.Fatalf("Expected -7.5, got %s", a)
+	}
+}
+
+func TestAmountOverflow(t *testing.T) {
+	if _, err := NewAmountChecked(1e13); err == nil {
+		t.Fatalf("Expected an error for 1e13")
+	}
+	if _, err := NewAmountChecked(math.NaN()); err == nil {
+		t.Fatalf("Expected an error for NaN")
+	}
+	if a, err := NewAmountChecked(-9e12); err != nil || a != MustParseAmount("-9e12") {
+		t.Fatalf("Expected -9e12, got %s, %v", a, err)
+	}
+	large := MustParseAmount("5e12")
+	if _, err := large.MulChecked(2); err == nil {
+		t.Fatalf("Expected an error multiplying 5e12 by 2")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected Mul to panic on overflow")
+		}
+	}()
+	large.Mul(2)
+}
+
+func TestAmountRound(t *testing.T) {
+	tests := []struct {
+		in, currency, want string
+	}{
+		{"2.345", "DKK", "2.35"},
+		{"-2.345", "DKK", "-2.35"},
+		{"2.344999", "DKK", "2.34"},
+		{"1234.5", "JPY", "1235"},
+		{"1.2345", "KWD", "1.235"},
+		{"1.005", "", "1.01"},
+	}
+	for _, test := range tests {
+		if got := MustParseAmount(test.in).Round(test.currency); got != MustParseAmount(test.want) {
+			t.Errorf("Round(%s, %s) = %s, expected %s", test.in, test.currency, got, test.want)
+		}
+	}
+	if s := MustParseAmount("1234.5").StringFixed(2); s != "1234.50" {
+		t.Fatalf("Expected 1234.50, got %s", s)
+	}
+	if minor := MustParseAmount("123.456").Minor("DKK"); minor != 12346 {
+		t.Fatalf("Expected 12346 øre, got %d", minor)
+	}
+	if a := AmountFromMinor(12345, "DKK"); a != MustParseAmount("123.45") {
+		t.Fatalf("Expected 123.45, got %s", a)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Number Amount  `json:"number"`
+		Quoted Amount  `json:"quoted"`
+		Null   Amount  `json:"null"`
+		Ptr    *Amount `json:"ptr,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 1050.25, "quoted": "-12.5", "null": null}`), &v); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if v.Number != MustParseAmount("1050.25") || v.Quoted != MustParseAmount("-12.5") || !v.Null.IsZero() {
+		t.Fatalf("Unexpected amounts %s, %s, %s", v.Number, v.Quoted, v.Null)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if string(data) != `{"number":1050.25,"quoted":-12.5,"null":0}` {
+		t.Fatalf("Unexpected JSON %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"number": "abc"}`), &v); err == nil {
+		t.Fatalf("Expected an error for an invalid amount")
+	}
+}
@@ -0,0 +1,165 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+// AccountRule maps bank transactions to the account they are booked
+// against. Every condition that is set must hold.
+type AccountRule struct {
+	Contains      string         // Text, compared case-insensitively, in the transaction's text or counterparty.
+	Match         *regexp.Regexp // Matched against the transaction's text and counterparty.
+	Direction     int            // 1 for money in only, -1 for money out only, 0 for both.
+	AccountNumber int
+	VatCode       string
+}
+
+func (rule AccountRule) matches(tx BankTransaction) bool {
+	if rule.Direction != 0 && tx.Amount.Sign() != rule.Direction {
+		return false
+	}
+	if rule.Contains != "" {
+		contains := strings.ToLower(rule.Contains)
+		if !strings.Contains(strings.ToLower(tx.Text), contains) && !strings.Contains(strings.ToLower(tx.Counterparty), contains) {
+			return false
+		}
+	}
+	if rule.Match != nil && !rule.Match.MatchString(tx.Text) && !rule.Match.MatchString(tx.Counterparty) {
+		return false
+	}
+	return true
+}
+
+// BankImportOptions configures ImportBankTransactions.
+type BankImportOptions struct {
+	JournalNumber     int
+	BankAccountNumber int // The account of the bank account; money in is debited to it.
+	EntryTypeNumber   int
+	// Rules map transactions to the contra account; the first rule that
+	// matches is used.
+	Rules []AccountRule
+	// DefaultAccountNumber is the contra account of transactions no rule
+	// matches, such as a suspense account. If 0, they are not imported.
+	DefaultAccountNumber int
+	// VoucherNumber returns the voucher number of a transaction. It must
+	// return the same number every time the transaction is imported, as
+	// that is how it is recognised. Defaults to BankVoucherNumber.
+	VoucherNumber func(tx BankTransaction, occurrence int) int
+	// DryRun maps and de-duplicates the transactions without creating
+	// entries.
+	DryRun bool
+}
+
+// BankImportResult is the outcome of importing one transaction.
+type BankImportResult struct {
+	Transaction BankTransaction
+	Entry       JournalEntry // The draft entry, with its EntryNumber once created.
+	Created     bool
+	Duplicate   bool // An entry for the transaction already exists, as a draft or booked.
+	Unmapped    bool // No rule matched and there is no default account.
+	Err         error
+}
+
+// BankVoucherNumber derives a voucher number between 100000000 and
+// 999999999 from the date, amount, currency, reference and text of tx.
+// occurrence tells identical transactions of one import apart: 0 for the
+// first, 1 for the second and so on.
+func BankVoucherNumber(tx BankTransaction, occurrence int) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%d", tx.BookingDate, tx.Amount, tx.Currency, tx.Reference, tx.Text, occurrence)
+	return 100_000_000 + int(h.Sum32()%900_000_000)
+}
+
+func (client *Client) ImportBankTransactions(txs []BankTransaction, opts BankImportOptions) ([]BankImportResult, error) {
+	return client.ImportBankTransactionsContext(context.Background(), txs, opts)
+}
+
+// ImportBankTransactionsContext creates a draft entry on the bank account
+// for each transaction, against the account its rule maps it to. Each
+// transaction gets its own voucher number (see BankImportOptions), and is
+// skipped as a duplicate if a draft or booked entry with that voucher number,
+// date and amount on the bank account exists, so a statement can be imported
+// again safely. The error is only set for invalid options; the outcome of
+// each transaction is in its result.
+func (client *Client) ImportBankTransactionsContext(ctx context.Context, txs []BankTransaction, opts BankImportOptions) ([]BankImportResult, error) {
+	if opts.JournalNumber == 0 || opts.BankAccountNumber == 0 {
+		return nil, fmt.Errorf("importing bank transactions needs a journal number and a bank account number")
+	}
+	voucherNumber := opts.VoucherNumber
+	if voucherNumber == nil {
+		voucherNumber = BankVoucherNumber
+	}
+	results := make([]BankImportResult, len(txs))
+	occurrences := map[BankTransaction]int{}
+	used := map[int]bool{}
+	for i, tx := range txs {
+		result := &results[i]
+		result.Transaction = tx
+		n := voucherNumber(tx, occurrences[tx])
+		occurrences[tx]++
+		if used[n] {
+			result.Err = fmt.Errorf("voucher number %d is used by another transaction of the import", n)
+			continue
+		}
+		used[n] = true
+		account, vatCode := opts.DefaultAccountNumber, ""
+		for _, rule := range opts.Rules {
+			if rule.matches(tx) {
+				account, vatCode = rule.AccountNumber, rule.VatCode
+				break
+			}
+		}
+		text := tx.Text
+		if text == "" {
+			text = tx.Counterparty
+		}
+		result.Entry = JournalEntry{
+			EntryTypeNumber:     opts.EntryTypeNumber,
+			VoucherNumber:       n,
+			JournalNumber:       opts.JournalNumber,
+			Date:                tx.BookingDate,
+			Amount:              tx.Amount,
+			Currency:            tx.Currency,
+			AccountNumber:       opts.BankAccountNumber,
+			ContraAccountNumber: account,
+			ContraVatCode:       vatCode,
+			Text:                text,
+		}
+		client.importBankTransaction(ctx, result, opts.DryRun)
+	}
+	return results, nil
+}
+
+func (client *Client) importBankTransaction(ctx context.Context, result *BankImportResult, dryRun bool) {
+	entry := &result.Entry
+	existing, err := client.GetAllJournalEntriesByVoucherNumberContext(ctx, entry.VoucherNumber)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	for _, e := range existing {
+		if e.Date == entry.Date && e.Amount == entry.Amount &&
+			(e.AccountNumber == entry.AccountNumber || e.ContraAccountNumber == entry.AccountNumber) {
+			result.Duplicate = true
+			return
+		}
+	}
+	if len(existing) > 0 {
+		result.Err = fmt.Errorf("voucher number %d is already used by other entries", entry.VoucherNumber)
+		return
+	}
+	if entry.ContraAccountNumber == 0 {
+		result.Unmapped = true
+		return
+	}
+	if dryRun {
+		return
+	}
+	if result.Err = client.CreateJournalEntryContext(ctx, entry); result.Err == nil {
+		result.Created = true
+	}
+}
@@ -0,0 +1,69 @@
+package economic
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestImportBankTransactions(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	txs := []BankTransaction{
+		{BookingDate: NewDate(2024, 3, 1), Amount: MustParseAmount("1250"), Currency: "DKK", Text: "Faktura 1042", Counterparty: "Kunde A/S"},
+		{BookingDate: NewDate(2024, 3, 1), Amount: MustParseAmount("-100"), Currency: "DKK", Text: "Husleje marts"},
+		{BookingDate: NewDate(2024, 3, 1), Amount: MustParseAmount("-25"), Currency: "DKK", Text: "Gebyr"},
+		{BookingDate: NewDate(2024, 3, 1), Amount: MustParseAmount("-25"), Currency: "DKK", Text: "Gebyr"},
+		{BookingDate: NewDate(2024, 3, 2), Amount: MustParseAmount("-9.95"), Currency: "DKK", Text: "Ukendt"},
+	}
+	opts := BankImportOptions{
+		JournalNumber:     1,
+		BankAccountNumber: 5820,
+		Rules: []AccountRule{
+			{Match: regexp.MustCompile(`(?i)^faktura \d+`), Direction: 1, AccountNumber: 5600},
+			{Contains: "HUSLEJE", AccountNumber: 3400, VatCode: "I25"},
+			{Contains: "gebyr", Direction: -1, AccountNumber: 7220},
+		},
+	}
+	results, err := client.ImportBankTransactions(txs, opts)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	for i, result := range results[:4] {
+		if result.Err != nil || !result.Created || result.Entry.EntryNumber == 0 {
+			t.Fatalf("Expected transaction %d to be created, got %+v", i, result)
+		}
+	}
+	if results[0].Entry.ContraAccountNumber != 5600 || results[1].Entry.ContraVatCode != "I25" || results[2].Entry.ContraAccountNumber != 7220 {
+		t.Fatalf("Unexpected mapping %+v", results)
+	}
+	if results[2].Entry.VoucherNumber == results[3].Entry.VoucherNumber {
+		t.Fatalf("Expected identical transactions to get their own voucher numbers")
+	}
+	if !results[4].Unmapped || results[4].Created {
+		t.Fatalf("Expected the unknown transaction to be left out, got %+v", results[4])
+	}
+	if n := len(srv.Items("draft-entries")); n != 4 {
+		t.Fatalf("Expected 4 draft entries, got %d", n)
+	}
+
+	// importing the statement again, after booking, creates nothing new
+	if err := client.BookAllEntries(1); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	opts.DefaultAccountNumber = 9990
+	results, err = client.ImportBankTransactions(txs, opts)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	for i, result := range results[:4] {
+		if result.Err != nil || !result.Duplicate || result.Created {
+			t.Fatalf("Expected transaction %d to be a duplicate, got %+v", i, result)
+		}
+	}
+	if !results[4].Created || results[4].Entry.ContraAccountNumber != 9990 {
+		t.Fatalf("Expected the unknown transaction on the default account, got %+v", results[4])
+	}
+
+	if _, err := client.ImportBankTransactions(txs, BankImportOptions{JournalNumber: 1}); err == nil {
+		t.Fatalf("Expected an error without a bank account")
+	}
+}
@@ -0,0 +1,387 @@
+package economic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// BankTransaction is one booked transaction on a bank statement.
+type BankTransaction struct {
+	BookingDate  Date
+	ValueDate    Date
+	Amount       Amount // Positive for money in, negative for money out.
+	Currency     string
+	Text         string // Remittance information or the bank's description.
+	Reference    string // The bank's reference of the transaction, if any.
+	Counterparty string // The payer of money in, or the payee of money out.
+}
+
+// BankStatement is the statement of one bank account.
+type BankStatement struct {
+	Account      string // IBAN or other account identification.
+	Currency     string
+	Transactions []BankTransaction
+}
+
+// camt053 is the part of an ISO 20022 camt.053 (BankToCustomerStatement)
+// document that is imported. Elements are matched by local name, so any
+// version of the schema is accepted.
+type camt053 struct {
+	Statements []struct {
+		Account struct {
+			IBAN     string `xml:"Id>IBAN"`
+			Other    string `xml:"Id>Othr>Id"`
+			Currency string `xml:"Ccy"`
+		} `xml:"Acct"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Status      struct {
+		Value string `xml:",chardata"`
+		Code  string `xml:"Cd"` // newer versions of the schema
+	} `xml:"Sts"`
+	BookingDate camtDate `xml:"BookgDt"`
+	ValueDate   camtDate `xml:"ValDt"`
+	Reference   string   `xml:"AcctSvcrRef"`
+	Info        string   `xml:"AddtlNtryInf"`
+	Details     []struct {
+		Amount       *camtAmount `xml:"Amt"`
+		Reference    string      `xml:"Refs>AcctSvcrRef"`
+		EndToEndId   string      `xml:"Refs>EndToEndId"`
+		Unstructured []string    `xml:"RmtInf>Ustrd"`
+		CreditorRef  string      `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+		Debtor       string      `xml:"RltdPties>Dbtr>Nm"`
+		DebtorParty  string      `xml:"RltdPties>Dbtr>Pty>Nm"`
+		Creditor     string      `xml:"RltdPties>Cdtr>Nm"`
+		CreditorPty  string      `xml:"RltdPties>Cdtr>Pty>Nm"`
+		Info         string      `xml:"AddtlTxInf"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (d camtDate) parse() (Date, error) {
+	s := strings.TrimSpace(d.Date)
+	if s == "" {
+		s = strings.TrimSpace(d.DateTime)
+	}
+	if s == "" {
+		return Date{}, nil
+	}
+	// DtTm may be without a time zone, which ParseDate does not accept
+	if len(s) > len(dateLayout) {
+		s = s[:len(dateLayout)]
+	}
+	return ParseDate(s)
+}
+
+// ParseCamt053 reads the booked transactions of the statements in a camt.053
+// document. An entry with several transactions, such as a batch payment, is
+// split into its transactions when they have their own amounts.
+func ParseCamt053(r io.Reader) ([]BankStatement, error) {
+	var doc camt053
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("reading camt.053: %w", err)
+	}
+	statements := []BankStatement{}
+	for _, stmt := range doc.Statements {
+		statement := BankStatement{Account: stmt.Account.IBAN, Currency: stmt.Account.Currency, Transactions: []BankTransaction{}}
+		if statement.Account == "" {
+			statement.Account = stmt.Account.Other
+		}
+		for i, entry := range stmt.Entries {
+			txs, err := entry.transactions(statement.Currency)
+			if err != nil {
+				return nil, fmt.Errorf("reading camt.053: entry %d of account %s: %w", i+1, statement.Account, err)
+			}
+			statement.Transactions = append(statement.Transactions, txs...)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func (entry camtEntry) transactions(currency string) ([]BankTransaction, error) {
+	status := strings.TrimSpace(entry.Status.Code)
+	if status == "" {
+		status = strings.TrimSpace(entry.Status.Value)
+	}
+	if status != "" && status != "BOOK" {
+		return nil, nil // pending or informational
+	}
+	sign := func(a Amount) (Amount, error) {
+		switch strings.TrimSpace(entry.CreditDebit) {
+		case "CRDT":
+			return a, nil
+		case "DBIT":
+			return a.Neg(), nil
+		}
+		return a, fmt.Errorf("unknown credit/debit indicator %q", entry.CreditDebit)
+	}
+	base := BankTransaction{Currency: currency, Reference: strings.TrimSpace(entry.Reference), Text: strings.TrimSpace(entry.Info)}
+	if entry.Amount.Currency != "" {
+		base.Currency = entry.Amount.Currency
+	}
+	var err error
+	if base.BookingDate, err = entry.BookingDate.parse(); err != nil {
+		return nil, err
+	}
+	if base.ValueDate, err = entry.ValueDate.parse(); err != nil {
+		return nil, err
+	}
+	total, err := ParseAmount(entry.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+	split := len(entry.Details) > 1
+	for _, d := range entry.Details {
+		split = split && d.Amount != nil
+	}
+	if !split {
+		tx := base
+		if tx.Amount, err = sign(total); err != nil {
+			return nil, err
+		}
+		if len(entry.Details) == 1 {
+			entry.fillDetails(&tx, 0)
+		}
+		return []BankTransaction{tx}, nil
+	}
+	txs := make([]BankTransaction, len(entry.Details))
+	for i, d := range entry.Details {
+		txs[i] = base
+		amount, err := ParseAmount(d.Amount.Value)
+		if err != nil {
+			return nil, err
+		}
+		if txs[i].Amount, err = sign(amount); err != nil {
+			return nil, err
+		}
+		if d.Amount.Currency != "" {
+			txs[i].Currency = d.Amount.Currency
+		}
+		entry.fillDetails(&txs[i], i)
+	}
+	return txs, nil
+}
+
+// fillDetails takes the text, reference and counterparty of tx from the
+// transaction details, where they are more specific than the entry's.
+func (entry camtEntry) fillDetails(tx *BankTransaction, i int) {
+	d := entry.Details[i]
+	text := strings.TrimSpace(strings.Join(d.Unstructured, " "))
+	for _, alt := range []string{d.CreditorRef, d.Info} {
+		if text == "" {
+			text = strings.TrimSpace(alt)
+		}
+	}
+	if text != "" {
+		tx.Text = text
+	}
+	for _, ref := range []string{d.Reference, d.EndToEndId} {
+		ref = strings.TrimSpace(ref)
+		if ref != "" && ref != "NOTPROVIDED" {
+			tx.Reference = ref
+			break
+		}
+	}
+	parties := []string{d.Debtor, d.DebtorParty}
+	if tx.Amount.Sign() < 0 {
+		parties = []string{d.Creditor, d.CreditorPty}
+	}
+	for _, name := range parties {
+		if name = strings.TrimSpace(name); name != "" {
+			tx.Counterparty = name
+			break
+		}
+	}
+}
+
+// CSVFormat describes a bank's CSV export. Columns are found by their
+// header, compared case-insensitively; the first header of each list that is
+// present is used.
+type CSVFormat struct {
+	Comma        rune     // Field separator.
+	DateLayouts  []string // Layouts tried for dates, in order.
+	DecimalComma bool     // Amounts are written as 1.234,56.
+
+	BookingDate  []string
+	ValueDate    []string
+	Text         []string
+	Amount       []string
+	Currency     []string
+	Reference    []string
+	Counterparty []string
+}
+
+// DanishBankCSV reads the semicolon separated exports with Danish headers
+// that Danish net banks commonly offer. Adjust a copy for a bank whose
+// headers differ.
+var DanishBankCSV = CSVFormat{
+	Comma:        ';',
+	DateLayouts:  []string{"02.01.2006", "02-01-2006", "02/01/2006", "2006-01-02", "2006/01/02"},
+	DecimalComma: true,
+	BookingDate:  []string{"Bogføringsdato", "Bogført", "Dato"},
+	ValueDate:    []string{"Rentedato", "Valørdato", "Valør"},
+	Text:         []string{"Tekst", "Posteringstekst", "Beskrivelse", "Navn"},
+	Amount:       []string{"Beløb", "Beløb i DKK", "Bevægelse"},
+	Currency:     []string{"Valutakode", "Møntsort"},
+	Reference:    []string{"Reference", "Bilagsnummer", "Bankreference"},
+	Counterparty: []string{"Modtager", "Afsender", "Modpart"},
+}
+
+// ParseBankCSV reads the transactions of a CSV export in format. Files that
+// are not valid UTF-8 are read as Latin-1, which older exports use. currency
+// is used for rows without a currency column.
+func ParseBankCSV(r io.Reader, format CSVFormat, currency string) ([]BankTransaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = format.Comma
+	if reader.Comma == 0 {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading bank CSV header: %w", err)
+	}
+	column := func(names []string) int {
+		for _, name := range names {
+			for i, h := range header {
+				if strings.EqualFold(strings.TrimSpace(h), name) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	cols := struct{ booking, value, text, amount, currency, ref, party int }{
+		column(format.BookingDate), column(format.ValueDate), column(format.Text), column(format.Amount),
+		column(format.Currency), column(format.Reference), column(format.Counterparty),
+	}
+	if cols.booking < 0 || cols.amount < 0 {
+		return nil, fmt.Errorf("bank CSV needs a date and an amount column, got %q", header)
+	}
+	txs := []BankTransaction{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bank CSV: %w", err)
+		}
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		tx := BankTransaction{
+			Currency:     currency,
+			Text:         field(cols.text),
+			Reference:    field(cols.ref),
+			Counterparty: field(cols.party),
+		}
+		if c := field(cols.currency); c != "" {
+			tx.Currency = c
+		}
+		if tx.BookingDate, err = format.parseDate(field(cols.booking)); err != nil {
+			return nil, fmt.Errorf("bank CSV line %d: %w", line, err)
+		}
+		if v := field(cols.value); v != "" {
+			if tx.ValueDate, err = format.parseDate(v); err != nil {
+				return nil, fmt.Errorf("bank CSV line %d: %w", line, err)
+			}
+		}
+		if tx.Amount, err = format.parseAmount(field(cols.amount)); err != nil {
+			return nil, fmt.Errorf("bank CSV line %d: %w", line, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (format CSVFormat) parseDate(s string) (Date, error) {
+	for _, layout := range format.DateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return DateOf(t), nil
+		}
+	}
+	return ParseDate(s)
+}
+
+func (format CSVFormat) parseAmount(s string) (Amount, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	thousands, decimal := ",", "."
+	if format.DecimalComma {
+		thousands, decimal = ".", ","
+	}
+	units, fraction, hasFraction := strings.Cut(s, decimal)
+	if strings.Contains(units, thousands) {
+		var err error
+		if units, err = ungroup(units, thousands); err != nil {
+			return Amount{}, fmt.Errorf("invalid amount %q: %w", s, err)
+		}
+	}
+	if hasFraction {
+		units += "." + fraction
+	}
+	return ParseAmount(units)
+}
+
+// ungroup removes the thousands separators from the integer part of an
+// amount, such as "-1.234.567". Anything but groups of three digits after
+// the first is an error, so that e.g. "1234.56" in a decimal comma export is
+// not read as 123456.
+func ungroup(units, separator string) (string, error) {
+	sign := ""
+	if units != "" && (units[0] == '-' || units[0] == '+') {
+		sign, units = units[:1], units[1:]
+	}
+	groups := strings.Split(units, separator)
+	for i, group := range groups {
+		valid := len(group) == 3 || (i == 0 && len(group) >= 1 && len(group) <= 3)
+		if !valid || strings.Trim(group, "0123456789") != "" {
+			return "", fmt.Errorf("%q is not a group of digits", group)
+		}
+	}
+	return sign + strings.Join(groups, ""), nil
+}
+
+func latin1ToUTF8(data []byte) []byte {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
@@ -0,0 +1,134 @@
+package economic
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCamt053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>STMT-1</MsgId><CreDtTm>2024-03-02T06:00:00</CreDtTm></GrpHdr>
+    <Stmt>
+      <Id>1</Id>
+      <Acct><Id><IBAN>DK5000400440116243</IBAN></Id><Ccy>DKK</Ccy></Acct>
+      <Ntry>
+        <Amt Ccy="DKK">1250.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-03-01</Dt></BookgDt>
+        <ValDt><Dt>2024-03-01</Dt></ValDt>
+        <AcctSvcrRef>BANK-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+          <RltdPties><Dbtr><Nm>Kunde A/S</Nm></Dbtr></RltdPties>
+          <RmtInf><Ustrd>Faktura 1042</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="DKK">300.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><DtTm>2024-03-01T14:30:00</DtTm></BookgDt>
+        <AcctSvcrRef>BANK-2</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Amt Ccy="DKK">100.00</Amt>
+            <Refs><EndToEndId>PAY-1</EndToEndId></Refs>
+            <RltdPties><Cdtr><Nm>Leverandør ApS</Nm></Cdtr></RltdPties>
+            <RmtInf><Ustrd>Husleje</Ustrd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Amt Ccy="DKK">200.00</Amt>
+            <Refs><EndToEndId>PAY-2</EndToEndId></Refs>
+            <RltdPties><Cdtr><Nm>El-selskabet</Nm></Cdtr></RltdPties>
+            <RmtInf><Ustrd>El</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="DKK">99.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2024-03-02</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestParseCamt053(t *testing.T) {
+	statements, err := ParseCamt053(strings.NewReader(testCamt053))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(statements) != 1 || statements[0].Account != "DK5000400440116243" || statements[0].Currency != "DKK" {
+		t.Fatalf("Unexpected statements %+v", statements)
+	}
+	txs := statements[0].Transactions
+	if len(txs) != 3 {
+		t.Fatalf("Expected the booked entry and the split batch, got %+v", txs)
+	}
+	first := txs[0]
+	if first.Amount != MustParseAmount("1250") || first.BookingDate != NewDate(2024, 3, 1) || first.Text != "Faktura 1042" ||
+		first.Reference != "BANK-1" || first.Counterparty != "Kunde A/S" || first.Currency != "DKK" {
+		t.Fatalf("Unexpected transaction %+v", first)
+	}
+	if txs[1].Amount != MustParseAmount("-100") || txs[1].Reference != "PAY-1" || txs[1].Counterparty != "Leverandør ApS" {
+		t.Fatalf("Unexpected transaction %+v", txs[1])
+	}
+	if txs[2].Amount != MustParseAmount("-200") || txs[2].Text != "El" || txs[2].BookingDate != NewDate(2024, 3, 1) {
+		t.Fatalf("Unexpected transaction %+v", txs[2])
+	}
+	if _, err := ParseCamt053(strings.NewReader("<Document>")); err == nil {
+		t.Fatalf("Expected an error for a truncated document")
+	}
+}
+
+func TestParseBankCSV(t *testing.T) {
+	csv := "\xef\xbb\xbfDato;Tekst;Beløb;Saldo\n" +
+		"01.03.2024;\"Faktura 1042; Kunde A/S\";1.250,00;11.250,00\n" +
+		"01.03.2024;Husleje;-100,50;11.149,50\n" +
+		";;;\n"
+	txs, err := ParseBankCSV(strings.NewReader(csv), DanishBankCSV, "DKK")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %+v", txs)
+	}
+	if txs[0].Amount != MustParseAmount("1250") || txs[0].Text != "Faktura 1042; Kunde A/S" || txs[0].BookingDate != NewDate(2024, 3, 1) || txs[0].Currency != "DKK" {
+		t.Fatalf("Unexpected transaction %+v", txs[0])
+	}
+	if txs[1].Amount != MustParseAmount("-100.5") {
+		t.Fatalf("Expected -100.5, got %s", txs[1].Amount)
+	}
+
+	// Latin-1 encoded export
+	latin1 := "Dato;Tekst;Bel\xf8b\n2024-03-04;Gebyr;-25\n"
+	txs, err = ParseBankCSV(strings.NewReader(latin1), DanishBankCSV, "DKK")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(txs) != 1 || txs[0].Amount != MustParseAmount("-25") {
+		t.Fatalf("Unexpected transactions %+v", txs)
+	}
+
+	if _, err := ParseBankCSV(strings.NewReader("Dato;Tekst\n01.03.2024;x\n"), DanishBankCSV, "DKK"); err == nil {
+		t.Fatalf("Expected an error without an amount column")
+	}
+	if _, err := ParseBankCSV(strings.NewReader("Dato;Beløb\n31.02.2024;1\n"), DanishBankCSV, "DKK"); err == nil {
+		t.Fatalf("Expected an error for an invalid date")
+	}
+	for _, amount := range []string{"1234.56", "1.23,45", "12.3456", "1..234"} {
+		if _, err := ParseBankCSV(strings.NewReader("Dato;Beløb\n01.03.2024;"+amount+"\n"), DanishBankCSV, "DKK"); err == nil {
+			t.Fatalf("Expected an error for the amount %s", amount)
+		}
+	}
+	txs, err = ParseBankCSV(strings.NewReader("Dato;Beløb\n01.03.2024;-1.234.567,8\n"), DanishBankCSV, "DKK")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if txs[0].Amount != MustParseAmount("-1234567.8") {
+		t.Fatalf("Expected -1234567.8, got %s", txs[0].Amount)
+	}
+}
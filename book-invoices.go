@@ -0,0 +1,122 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const defaultBookingConcurrency = 4
+
+// ErrNoSendBy is returned when a batch is booked without choosing how the
+// invoices are sent. Booking used to email them by default; set SendBy to
+// SendByEmail for that, or to SendByNone to only book them.
+var ErrNoSendBy = errors.New("choose how booked invoices are sent: set SendBy, e.g. to SendByNone or SendByEmail")
+
+// BatchBookOptions configures BookInvoices.
+type BatchBookOptions struct {
+	SendBy      SendBy // How to send every booked invoice. Required; SendByNone books without sending.
+	Concurrency int    // Drafts booked at the same time. Defaults to 4.
+}
+
+// BookResult is the outcome of booking one draft in a batch.
+type BookResult struct {
+	DraftInvoiceNumber  int
+	BookedInvoiceNumber int     // Set when the draft was booked.
+	Invoice             Invoice // The booked invoice.
+	Skipped             bool    // The draft did not exist (any more), e.g. because it was already booked, or it was listed twice.
+	Err                 error
+}
+
+func (client *Client) BookInvoices(draftNumbers []int, opts BatchBookOptions) []BookResult {
+	return client.BookInvoicesContext(context.Background(), draftNumbers, opts)
+}
+
+// BookInvoicesContext books the drafts, at most opts.Concurrency at a time,
+// and returns one result per draft in the order given. A failed draft does
+// not stop the others; once ctx is cancelled the remaining drafts fail with
+// its error. Combine with the client's Throttle to stay within the API's
+// rate limits.
+func (client *Client) BookInvoicesContext(ctx context.Context, draftNumbers []int, opts BatchBookOptions) []BookResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBookingConcurrency
+	}
+	results := make([]BookResult, len(draftNumbers))
+	if opts.SendBy == "" {
+		for i, draftNo := range draftNumbers {
+			results[i] = BookResult{DraftInvoiceNumber: draftNo, Err: ErrNoSendBy}
+		}
+		return results
+	}
+	seen := map[int]bool{}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, draftNo := range draftNumbers {
+		results[i].DraftInvoiceNumber = draftNo
+		if seen[draftNo] {
+			results[i].Skipped = true
+			continue
+		}
+		seen[draftNo] = true
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(result *BookResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			client.bookOne(ctx, result, opts.SendBy)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func (client *Client) bookOne(ctx context.Context, result *BookResult, sendBy SendBy) {
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return
+	}
+	if _, err := client.GetDraftInvoiceContext(ctx, result.DraftInvoiceNumber); err != nil {
+		if IsNotFound(err) {
+			result.Skipped = true
+			return
+		}
+		result.Err = err
+		return
+	}
+	invoice, err := client.BookInvoiceContext(ctx, result.DraftInvoiceNumber, BookInvoiceOptions{SendBy: sendBy})
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.Invoice = invoice
+	result.BookedInvoiceNumber = invoice.BookedInvoiceNumber
+}
+
+func (client *Client) BookInvoicesMatching(filter *Filter, opts BatchBookOptions) ([]BookResult, error) {
+	return client.BookInvoicesMatchingContext(context.Background(), filter, opts)
+}
+
+// BookInvoicesMatchingContext books the drafts matching filter (all drafts
+// if filter is nil). The error is only set if the drafts could not be
+// listed; the result of each draft is in its BookResult.
+func (client *Client) BookInvoicesMatchingContext(ctx context.Context, filter *Filter, opts BatchBookOptions) ([]BookResult, error) {
+	if opts.SendBy == "" {
+		return nil, ErrNoSendBy
+	}
+	tc := &TypedClient[Invoice]{client: client}
+	drafts, err := tc.getEntities(ctx, "invoices/drafts", ListOptions{PageSize: invoicePageSize, Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, len(drafts))
+	for i, draft := range drafts {
+		numbers[i] = draft.DraftInvoiceNumber
+	}
+	return client.BookInvoicesContext(ctx, numbers, opts), nil
+}
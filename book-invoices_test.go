@@ -0,0 +1,88 @@
+package economic
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBookInvoices(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	var drafts []int
+	for _, ref := range []string{"batch-1", "batch-2", "batch-3"} {
+		draft, err := client.CreateInvoice(testOrder(ref))
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		drafts = append(drafts, draft.DraftInvoiceNumber)
+	}
+	srv.FailNext(http.MethodPost, "invoices/booked", http.StatusBadRequest)
+
+	results := client.BookInvoices([]int{drafts[0], drafts[1], drafts[1], 999}, BatchBookOptions{SendBy: SendByNone, Concurrency: 2})
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %+v", results)
+	}
+	failed, booked := 0, 0
+	for _, r := range results[:2] {
+		if r.Err != nil {
+			failed++
+		} else if r.BookedInvoiceNumber != 0 {
+			booked++
+		}
+	}
+	if failed != 1 || booked != 1 {
+		t.Fatalf("Expected one booked and one failed draft, got %+v", results[:2])
+	}
+	if !results[2].Skipped || !results[3].Skipped || results[3].Err != nil {
+		t.Fatalf("Expected the duplicate and the missing draft to be skipped, got %+v", results[2:])
+	}
+
+	filter := &Filter{}
+	filter.AndCondition("references.other", FilterOperatorEquals, "batch-3")
+	results, err := client.BookInvoicesMatching(filter, BatchBookOptions{SendBy: SendByEAN})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(results) != 1 || results[0].DraftInvoiceNumber != drafts[2] || results[0].Err != nil {
+		t.Fatalf("Expected draft %d to be booked, got %+v", drafts[2], results)
+	}
+	var body map[string]any
+	requests := srv.Requests()
+	last := requests[len(requests)-1]
+	if err := json.Unmarshal(last.Body, &body); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if body["sendBy"] != "ean" {
+		t.Fatalf("Expected sendBy ean, got %s", last.Body)
+	}
+
+	// without a choice, and with SendByNone, nothing is sent
+	for _, opts := range [][]BookInvoiceOptions{nil, {{SendBy: SendByNone}}} {
+		draft, err := client.CreateInvoice(testOrder("batch-none"))
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if _, err := client.BookInvoice(draft.DraftInvoiceNumber, opts...); err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		requests := srv.Requests()
+		last := requests[len(requests)-1]
+		body = nil
+		if err := json.Unmarshal(last.Body, &body); err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if _, ok := body["sendBy"]; ok {
+			t.Fatalf("Expected no sendBy, got %s", last.Body)
+		}
+	}
+	if results := client.BookInvoices(drafts, BatchBookOptions{}); len(results) != len(drafts) || !errors.Is(results[0].Err, ErrNoSendBy) {
+		t.Fatalf("Expected ErrNoSendBy without a SendBy, got %+v", results)
+	}
+	if _, err := client.BookInvoicesMatching(nil, BatchBookOptions{}); !errors.Is(err, ErrNoSendBy) {
+		t.Fatalf("Expected ErrNoSendBy without a SendBy, got %v", err)
+	}
+	if _, err := client.BookInvoice(drafts[0], BookInvoiceOptions{SendBy: "fax"}); err == nil {
+		t.Fatalf("Expected an error for an unknown SendBy")
+	}
+}
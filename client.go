@@ -2,38 +2,114 @@ package economic
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-type APIError struct {
-	StatusCode int
-	body       string
+// Client talks to both the REST API and the newer OpenAPI endpoints of
+// e-conomic. Every exported method Foo has a FooContext counterpart taking a
+// context.Context; cancelling it aborts both the in-flight request and any
+// retry backoff. Foo itself is FooContext with context.Background().
+type Client struct {
+	AgreementGrant string `json:"agreement_grant"`
+	AppSecretToken string `json:"app_secret"`
+
+	// Optional transport settings; the zero value talks to the public
+	// e-conomic endpoints using http.DefaultClient.
+	HTTPClient     *http.Client `json:"-"`                          // Used for all requests, e.g. to set timeouts, proxies or TLS config.
+	RestBaseURL    string       `json:"rest_base_url,omitempty"`    // Base URL of the REST API. Defaults to DefaultRestBaseURL.
+	OpenAPIBaseURL string       `json:"openapi_base_url,omitempty"` // Base URL of the OpenAPI endpoints (journals, dimensions, ...). Defaults to DefaultOpenAPIBaseURL.
+	UserAgent      string       `json:"user_agent,omitempty"`       // Sent as the User-Agent header when set.
+
+	// Logger receives structured logs of every call. Nothing is logged when
+	// nil. Tokens and personal data are redacted before reaching it.
+	Logger *slog.Logger `json:"-"`
+
+	// Throttle, when set, limits the request rate and concurrency of both
+	// APIs. Share it between clients for the same agreement.
+	Throttle *Throttle `json:"-"`
+
+	// RetryPolicy decides which failed requests are retried; the zero value
+	// is RetrySafe. Override it per call with WithRetryPolicy.
+	RetryPolicy RetryPolicy `json:"-"`
+
+	// Registry caches reference data such as layouts and VAT zones. It is
+	// created on first use; set it to share a cache or to give it a TTL.
+	Registry *Registry `json:"-"`
+
+	maxPageSizeAllowed int64 // as last reported by the REST API; accessed atomically
 }
 
-func (e *APIError) Error() string {
-	return e.body
+// maxPageSize is the largest page size the REST API accepts: what it last
+// reported, or MAX_PAGE_SIZE before any collection was fetched.
+func (client *Client) maxPageSize() int {
+	if n := atomic.LoadInt64(&client.maxPageSizeAllowed); n > 0 {
+		return int(n)
+	}
+	return MAX_PAGE_SIZE
 }
 
-type Client struct {
-	AgreementGrant string `json:"agreement_grant"`
-	AppSecretToken string `json:"app_secret"`
+const (
+	DefaultRestBaseURL    = "https://restapi.e-conomic.com"
+	DefaultOpenAPIBaseURL = "https://apis.e-conomic.com"
+)
+
+func (client *Client) httpClient() *http.Client {
+	if client.HTTPClient != nil {
+		return client.HTTPClient
+	}
+	return http.DefaultClient
 }
 
-func (client *Client) assertClientIsConfigured() {
+func (client *Client) restBaseURL() string {
+	if client.RestBaseURL != "" {
+		return strings.TrimRight(client.RestBaseURL, "/")
+	}
+	return DefaultRestBaseURL
+}
+
+func (client *Client) openAPIBaseURL() string {
+	if client.OpenAPIBaseURL != "" {
+		return strings.TrimRight(client.OpenAPIBaseURL, "/")
+	}
+	return DefaultOpenAPIBaseURL
+}
+
+// setHeaders sets the headers shared by the REST and OpenAPI requests.
+func (client *Client) setHeaders(req *http.Request) {
+	req.Header.Set("X-AppSecretToken", client.AppSecretToken)
+	req.Header.Set("X-AgreementGrantToken", client.AgreementGrant)
+	req.Header.Set("Accept", "application/json")
+	if client.UserAgent != "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
+}
+
+// ErrClientNotConfigured is returned by every call on a Client that lacks
+// an agreement grant or app secret token.
+var ErrClientNotConfigured = errors.New("e-conomic client is not configured: missing agreement grant or app secret token")
+
+func (client *Client) checkClientIsConfigured() error {
 	if len(client.AgreementGrant) == 0 || len(client.AppSecretToken) == 0 {
-		panic(fmt.Sprintf("client is not configured: %#v", client))
+		return ErrClientNotConfigured
 	}
+	return nil
 }
 
 const (
 	DEFAULT_PAGE_SIZE = 100
+	MAX_PAGE_SIZE     = 1000 // the largest pagesize the REST API accepts
 	maxRetries        = 4
 	baseDelay         = time.Second
 )
@@ -42,227 +118,396 @@ func isRetryableStatus(code int) bool {
 	return code == http.StatusTooManyRequests || code >= 500
 }
 
-// backoffDelay returns how long to wait before the next attempt. For 429
-// responses it respects the Retry-After header when present; otherwise it
-// uses plain exponential backoff (1s, 2s, 4s, 8s).
+// backoffDelay returns how long to wait before the next attempt. It respects
+// the Retry-After header (seconds or an HTTP-date) when present; otherwise it
+// uses exponential backoff (1s, 2s, 4s, 8s) with jitter, so that workers
+// throttled at the same time do not retry in lockstep.
 func backoffDelay(attempt int, res *http.Response) time.Duration {
-	if res != nil && res.StatusCode == http.StatusTooManyRequests {
-		if s := res.Header.Get("Retry-After"); s != "" {
-			if secs, err := strconv.Atoi(s); err == nil {
-				return time.Duration(secs) * time.Second
-			}
+	if res != nil {
+		if d, ok := retryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			return d
 		}
 	}
-	return baseDelay * (1 << attempt) // left bit shift
+	return jitter(baseDelay * (1 << attempt)) // left bit shift
+}
+
+// retryAfter parses a Retry-After value relative to now.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(secs)*time.Second), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(0, t.Sub(now)), true
+	}
+	return 0, false
 }
 
-func (client *Client) callRestAPI(endpoint, method string, request, response any) error {
-	client.assertClientIsConfigured()
-	url := fmt.Sprintf("https://restapi.e-conomic.com/%s", endpoint)
+// jitter returns a random duration between d/2 and d.
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// sleepContext waits for d, returning early with the context's error if ctx
+// is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do sends req once the throttle allows it and reads the whole response
+// body, holding the throttle's slot until then.
+func (client *Client) do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	release, err := client.Throttle.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	res, err := client.httpClient().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body when calling e-conomic (%s %s => %d): %w", req.Method, req.URL.Path, res.StatusCode, err)
+	}
+	return res, body, nil
+}
+
+// logEndpoint strips the query string, which may hold filters on personal
+// data, from an endpoint before it is logged.
+func logEndpoint(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	return path
+}
+
+// callRestAPI sends request to the REST API and decodes the JSON reply into
+// response; use download for files such as PDFs. Writes may pass a lookup,
+// used before a failed attempt is sent again (see RetryPolicy).
+func (client *Client) callRestAPI(ctx context.Context, endpoint, method string, request, response any, lookup ...lookupFunc) error {
+	if err := client.checkClientIsConfigured(); err != nil {
+		return err
+	}
+	logger := client.logger().With("api", "REST", "method", method, "endpoint", logEndpoint(endpoint))
+	url := fmt.Sprintf("%s/%s", client.restBaseURL(), endpoint)
 	jsonRequest, err := json.Marshal(request)
 	if err != nil {
-		log.Printf("error in marshalling request: %s", err)
+		logger.Error("error in marshalling request", "error", err)
 		return err
 	}
 	if request == nil {
 		jsonRequest = []byte{}
 	}
 
+	var lookupFn lookupFunc
+	if len(lookup) > 0 {
+		lookupFn = lookup[0]
+	}
 	var lastErr error
 	var lastRes *http.Response
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			delay := backoffDelay(attempt-1, lastRes)
-			log.Printf("retrying e-conomic/REST %s %s (attempt %d/%d) after %s", method, endpoint, attempt, maxRetries, delay)
-			time.Sleep(delay)
+			logger.Info("retrying e-conomic", "attempt", attempt, "maxRetries", maxRetries, "delay", delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			lastStatus := 0
+			if lastRes != nil {
+				lastStatus = lastRes.StatusCode
+			}
+			done, err := client.beforeResend(ctx, method, lastStatus, lastErr, lookupFn)
+			if err != nil {
+				return err
+			}
+			if done {
+				logger.Info("earlier attempt took effect, not sending it again", "attempt", attempt)
+				return nil
+			}
 			lastRes = nil
 		}
 
-		req, err := http.NewRequest(method, url, bytes.NewReader(jsonRequest))
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(jsonRequest))
 		if err != nil {
 			return err
 		}
-		req.Header.Set("X-AppSecretToken", client.AppSecretToken)
-		req.Header.Set("X-AgreementGrantToken", client.AgreementGrant)
+		client.setHeaders(req)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
 
-		res, err := http.DefaultClient.Do(req)
+		start := time.Now()
+		res, body, err := client.do(ctx, req)
 		if err != nil {
-			log.Printf("error in calling e-conomic (%s %s) err: %s", url, method, err)
+			logger.Warn("error in calling e-conomic", "attempt", attempt, "duration", time.Since(start), "error", err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !client.mayRetry(ctx, method, 0, lookupFn) {
+				return err
+			}
 			lastErr = err
 			continue
 		}
-
-		body := new(bytes.Buffer)
-		body.ReadFrom(res.Body)
-		res.Body.Close()
-		log.Printf("e-conomic/REST %s %s => %d", method, endpoint, res.StatusCode)
+		logger.Debug("e-conomic call", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start))
 
 		if isRetryableStatus(res.StatusCode) {
-			lastErr = &APIError{
-				StatusCode: res.StatusCode,
-				body:       fmt.Sprintf("error calling e-conomic (%s %s) err: %s", url, method, body.String()),
+			lastErr = newAPIError(method, endpoint, res.StatusCode, body)
+			if !client.mayRetry(ctx, method, res.StatusCode, lookupFn) {
+				logger.Error("error calling e-conomic, not retrying write", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", lastErr)
+				return lastErr
 			}
-			log.Printf("will retry e-conomic/REST %s %s (attempt %d/%d): %s", method, endpoint, attempt+1, maxRetries, body.String())
+			logger.Warn("will retry e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", lastErr)
 			lastRes = res
 			continue
 		}
 
 		if res.StatusCode >= 400 {
-			log.Printf("error calling e-conomic (%s %s) err: %s", url, method, body.String())
-			return &APIError{
-				StatusCode: res.StatusCode,
-				body:       fmt.Sprintf("error calling e-conomic (%s %s) err: %s", url, method, body.String()),
-			}
+			apiErr := newAPIError(method, endpoint, res.StatusCode, body)
+			logger.Error("error calling e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", apiErr)
+			return apiErr
 		}
 
 		if response == nil {
 			return nil
 		}
-		return json.Unmarshal(body.Bytes(), response)
+		return json.Unmarshal(body, response)
 	}
 	return lastErr
 }
 
-func (client *Client) callAPI(endpoint string, method string, params url.Values, body any, response any) error {
+// download streams the body of a GET on a REST endpoint, such as a PDF, to
+// w. Like callRestAPI it retries throttling, 5xx and network errors, but only
+// until the first byte has been written to w.
+func (client *Client) download(ctx context.Context, endpoint string, w io.Writer) (int64, error) {
+	if err := client.checkClientIsConfigured(); err != nil {
+		return 0, err
+	}
+	logger := client.logger().With("api", "REST", "method", http.MethodGet, "endpoint", logEndpoint(endpoint))
+	url := fmt.Sprintf("%s/%s", client.restBaseURL(), endpoint)
+
+	var lastErr error
+	var lastRes *http.Response
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoffDelay(attempt-1, lastRes)
+			logger.Info("retrying e-conomic", "attempt", attempt, "maxRetries", maxRetries, "delay", delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return 0, err
+			}
+			lastRes = nil
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return 0, err
+		}
+		client.setHeaders(req)
+		req.Header.Set("Accept", "*/*")
+
+		start := time.Now()
+		res, body, n, err := client.stream(ctx, req, w)
+		if err != nil {
+			logger.Warn("error in downloading from e-conomic", "attempt", attempt, "bytes", n, "duration", time.Since(start), "error", err)
+			if ctx.Err() != nil {
+				return n, ctx.Err()
+			}
+			if n > 0 || !client.mayRetry(ctx, http.MethodGet, 0, nil) {
+				return n, err
+			}
+			lastErr = err
+			continue
+		}
+		if isRetryableStatus(res.StatusCode) {
+			lastErr = newAPIError(http.MethodGet, endpoint, res.StatusCode, body)
+			if !client.mayRetry(ctx, http.MethodGet, res.StatusCode, nil) {
+				return 0, lastErr
+			}
+			logger.Warn("will retry e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", lastErr)
+			lastRes = res
+			continue
+		}
+		if res.StatusCode >= 400 {
+			apiErr := newAPIError(http.MethodGet, endpoint, res.StatusCode, body)
+			logger.Error("error calling e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", apiErr)
+			return 0, apiErr
+		}
+		logger.Debug("e-conomic download", "status", res.StatusCode, "bytes", n, "attempt", attempt, "duration", time.Since(start))
+		return n, nil
+	}
+	return 0, lastErr
+}
+
+// stream is do for downloads: a successful response body is copied to w
+// instead of being read into memory; an error body is returned as body.
+func (client *Client) stream(ctx context.Context, req *http.Request, w io.Writer) (res *http.Response, body []byte, n int64, err error) {
+	release, err := client.Throttle.acquire(ctx)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer release()
+	res, err = client.httpClient().Do(req)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		body, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to read response body when calling e-conomic (%s %s => %d): %w", req.Method, req.URL.Path, res.StatusCode, err)
+		}
+		return res, body, 0, nil
+	}
+	n, err = io.Copy(w, res.Body)
+	return res, nil, n, err
+}
+
+// restEndpoint turns a link returned by the REST API, such as
+// Invoice.Pdf.Download, into an endpoint for callRestAPI. It refuses links to
+// other hosts, so the agreement's tokens are only sent to e-conomic.
+func (client *Client) restEndpoint(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid link %q: %w", link, err)
+	}
+	base, err := url.Parse(client.restBaseURL())
+	if err != nil {
+		return "", fmt.Errorf("invalid REST base URL %q: %w", client.restBaseURL(), err)
+	}
+	prefix := strings.TrimSuffix(base.Path, "/") + "/"
+	if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.Path, prefix) {
+		return "", fmt.Errorf("link %q is not a link to the e-conomic REST API at %s", link, client.restBaseURL())
+	}
+	endpoint := strings.TrimPrefix(u.Path, prefix)
+	if u.RawQuery != "" {
+		endpoint += "?" + u.RawQuery
+	}
+	return endpoint, nil
+}
+
+// callAPI is callRestAPI for the OpenAPI endpoints.
+func (client *Client) callAPI(ctx context.Context, endpoint string, method string, params url.Values, body any, response any, lookup ...lookupFunc) error {
 	if params == nil {
 		params = url.Values{}
 	}
-	client.assertClientIsConfigured()
-	grant := client.AgreementGrant
-	secret := client.AppSecretToken
-	if grant == "" || secret == "" {
-		panic("missing agreement grant or app secret")
+	if err := client.checkClientIsConfigured(); err != nil {
+		return err
 	}
+	logger := client.logger().With("api", "OpenAPI", "method", method, "endpoint", logEndpoint(endpoint))
 
 	var jsonBody []byte
 	if body != nil {
 		var err error
 		jsonBody, err = json.Marshal(body)
 		if err != nil {
-			log.Printf("error in marshalling request: %s", err)
+			logger.Error("error in marshalling request", "error", err)
 			return err
 		}
 	}
 
-	reqURL := &url.URL{
-		Path:     endpoint,
-		RawQuery: params.Encode(),
-		Scheme:   "https",
-		Host:     "apis.e-conomic.com",
+	reqURL, err := url.Parse(client.openAPIBaseURL())
+	if err != nil {
+		return err
 	}
+	reqURL.Path += endpoint
+	reqURL.RawQuery = params.Encode()
 
+	var lookupFn lookupFunc
+	if len(lookup) > 0 {
+		lookupFn = lookup[0]
+	}
 	var lastErr error
 	var lastRes *http.Response
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			delay := backoffDelay(attempt-1, lastRes)
-			log.Printf("retrying e-conomic/OpenAPI %s %s (attempt %d/%d) after %s", method, endpoint, attempt, maxRetries, delay)
-			time.Sleep(delay)
+			logger.Info("retrying e-conomic", "attempt", attempt, "maxRetries", maxRetries, "delay", delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			lastStatus := 0
+			if lastRes != nil {
+				lastStatus = lastRes.StatusCode
+			}
+			done, err := client.beforeResend(ctx, method, lastStatus, lastErr, lookupFn)
+			if err != nil {
+				return err
+			}
+			if done {
+				logger.Info("earlier attempt took effect, not sending it again", "attempt", attempt)
+				return nil
+			}
 			lastRes = nil
 		}
 
-		req := &http.Request{
+		req := (&http.Request{
 			Method: method,
 			URL:    reqURL,
 			Header: make(http.Header),
-		}
-		req.Header.Set("X-AppSecretToken", secret)
-		req.Header.Set("X-AgreementGrantToken", grant)
-		req.Header.Set("Accept", "application/json")
+		}).WithContext(ctx)
+		client.setHeaders(req)
 		if jsonBody != nil {
 			req.Header.Set("Content-Type", "application/json")
 			req.Body = io.NopCloser(bytes.NewReader(jsonBody))
 		}
 
-		res, err := http.DefaultClient.Do(req)
+		start := time.Now()
+		res, resBody, err := client.do(ctx, req)
 		if err != nil {
-			log.Printf("error in calling e-conomic (%s %s) err: %s", endpoint, method, err)
+			logger.Warn("error in calling e-conomic", "attempt", attempt, "duration", time.Since(start), "error", err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !client.mayRetry(ctx, method, 0, lookupFn) {
+				return err
+			}
 			lastErr = err
 			continue
 		}
 
 		if isRetryableStatus(res.StatusCode) {
-			resBody, _ := io.ReadAll(res.Body)
-			res.Body.Close()
-			lastErr = fmt.Errorf("error in calling e-conomic (%s %s => %d) %s", method, endpoint, res.StatusCode, string(resBody))
-			log.Printf("will retry e-conomic/OpenAPI %s %s (attempt %d/%d): %s", method, endpoint, attempt+1, maxRetries, string(resBody))
+			lastErr = newAPIError(method, endpoint, res.StatusCode, resBody)
+			if !client.mayRetry(ctx, method, res.StatusCode, lookupFn) {
+				logger.Error("error calling e-conomic, not retrying write", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", lastErr)
+				return lastErr
+			}
+			logger.Warn("will retry e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", lastErr)
 			lastRes = res
 			continue
 		}
 
 		if res.StatusCode >= 400 {
-			resBody, err := io.ReadAll(res.Body)
-			res.Body.Close()
-			if err != nil {
-				return fmt.Errorf("failed to read response body (internal error?) when calling e-conomic (%s %s => %d)", method, endpoint, res.StatusCode)
-			}
-			return fmt.Errorf("error in calling e-conomic (%s %s => %d) %s", method, endpoint, res.StatusCode, string(resBody))
+			apiErr := newAPIError(method, endpoint, res.StatusCode, resBody)
+			logger.Error("error calling e-conomic", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", apiErr)
+			return apiErr
 		}
 
 		if response != nil {
-			err = json.NewDecoder(res.Body).Decode(response)
+			err = json.Unmarshal(resBody, response)
 		}
-		res.Body.Close()
-		log.Printf("e-conomic/OpenAPI %s %s => %d", method, endpoint, res.StatusCode)
+		logger.Debug("e-conomic call", "status", res.StatusCode, "attempt", attempt, "duration", time.Since(start))
 		return err
 	}
 	return lastErr
 }
 
-// getEntities uses the REST API (callRestAPI) and CollectionReponse. Determines remaining pages from Pagination.Results in the first response.
-func (tc *TypedClient[T]) getEntities(baseUrl string, pageSize int, filter string) (entities []T, err error) {
-	client := tc.client
-	results := CollectionReponse[T]{}
-	url := baseUrl + fmt.Sprintf("?filter=%s&pagesize=%d", filter, pageSize)
-	err = client.callRestAPI(url, http.MethodGet, nil, &results)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-		return
-	}
-	entities = results.Collection
-	numberOfResults := results.Pagination.Results
-	numberOfPages := (numberOfResults / pageSize) + 1 // integer division (disregarding the remainder)
-	if numberOfPages > 1 {
-		for i := 1; i < numberOfPages; i++ {
-			url := fmt.Sprintf("%s?skippages=%d&pagesize=%d", baseUrl, i, pageSize)
-			fmt.Printf("URL: %s\n", url)
-			err = client.callRestAPI(url, http.MethodGet, nil, &results)
-			if err != nil {
-				log.Printf("ERROR: %#v", err)
-				return
-			}
-			entities = append(entities, results.Collection...)
-		}
-	}
-	return
+// getEntities fetches all entities of a REST collection (callRestAPI and
+// CollectionReponse). Use TypedClient.pager to process them page by page
+// instead.
+func (tc *TypedClient[T]) getEntities(ctx context.Context, baseUrl string, opts ListOptions) ([]T, error) {
+	return tc.pager(baseUrl, opts).Collect(ctx)
 }
 
 // getAllCursor fetches all items from a cursor-based pagination endpoint.
-// The cursor is absent in the response when there are no more items.
-func getAllCursor[T any](client *Client, baseURL string, params url.Values) ([]T, error) {
-	var all []T
-	cursor := ""
-	for {
-		p := url.Values{}
-		for k, v := range params {
-			p[k] = v
-		}
-		if cursor != "" {
-			p.Set("cursor", cursor)
-		}
-		resp := CursorResponse[T]{}
-		if err := client.callAPI(baseURL, http.MethodGet, p, nil, &resp); err != nil {
-			return nil, err
-		}
-		all = append(all, resp.Items...)
-		if resp.Cursor == "" {
-			break
-		}
-		cursor = resp.Cursor
-	}
-	return all, nil
+// Use newCursorPager to process them page by page instead.
+func getAllCursor[T any](ctx context.Context, client *Client, baseURL string, params url.Values) ([]T, error) {
+	return newCursorPager[T](client, baseURL, params).Collect(ctx)
 }
 
 // function to get the last entity (e.g. customer contact)?
@@ -0,0 +1,77 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSleepContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := sleepContext(ctx, time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("Expected sleep to be aborted, waited %s", time.Since(start))
+	}
+}
+
+type countingTransport struct {
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestConfigurableTransport(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "econ-test/1.0" {
+			t.Errorf("Expected user agent econ-test/1.0, got %s", r.Header.Get("User-Agent"))
+		}
+		if r.Header.Get("X-AgreementGrantToken") != "grant" || r.Header.Get("X-AppSecretToken") != "secret" {
+			t.Errorf("Missing auth headers: %v", r.Header)
+		}
+		paths = append(paths, r.URL.RequestURI())
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	transport := &countingTransport{}
+	client := &Client{
+		AgreementGrant: "grant",
+		AppSecretToken: "secret",
+		HTTPClient:     &http.Client{Transport: transport},
+		RestBaseURL:    server.URL + "/rest/",
+		OpenAPIBaseURL: server.URL + "/open",
+		UserAgent:      "econ-test/1.0",
+	}
+	resp := map[string]any{}
+	if err := client.callRestAPI(context.Background(), "customers/1", http.MethodGet, nil, &resp); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	params := url.Values{"filter": {"voucherNumber$eq:1"}}
+	if err := client.callAPI(context.Background(), "/journalsapi/v1/draft-entries", http.MethodGet, params, nil, &resp); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	expected := []string{"/rest/customers/1", "/open/journalsapi/v1/draft-entries?filter=voucherNumber%24eq%3A1"}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %d requests, got %v", len(expected), paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], paths[i])
+		}
+	}
+	if transport.calls != 2 {
+		t.Fatalf("Expected the custom http.Client to be used twice, got %d", transport.calls)
+	}
+}
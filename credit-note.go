@@ -0,0 +1,146 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrOverCredit is returned when a credit note would credit more of an
+// invoice line than is left after earlier credit notes.
+var ErrOverCredit = errors.New("credit note exceeds what is left to credit")
+
+// CreditLine selects a line of the invoice to credit. Set Quantity to credit
+// part of the quantity, or Amount to credit a net amount in the invoice
+// currency; with neither, the whole line is credited.
+type CreditLine struct {
+	LineNumber int     // The line on the booked invoice.
+	Quantity   float64 // The quantity to credit, at the line's unit price and discount.
+	Amount     Amount  // The net amount to credit, as one unit without discount.
+}
+
+// CreditNoteRef is the references.other that links credit notes to invoice
+// by its booked invoice number: "<reference> (invoice <number>)", or
+// "Invoice <number>" if the invoice has no reference.
+func CreditNoteRef(invoice Invoice) string {
+	if invoice.References != nil && invoice.References.Other != "" {
+		return fmt.Sprintf("%s (invoice %d)", invoice.References.Other, invoice.BookedInvoiceNumber)
+	}
+	return fmt.Sprintf("Invoice %d", invoice.BookedInvoiceNumber)
+}
+
+// creditKey identifies what a line charges for across an invoice and its
+// credit notes, as credit note lines need not keep the invoice's line
+// numbers: the product and the description.
+func creditKey(line OrderLine) string {
+	product := ""
+	if line.Product != nil {
+		product = line.Product.ProductNumber
+	}
+	return product + "\x00" + line.Description
+}
+
+// creditLines returns the credit note lines for the selected lines of
+// invoice, or for all of them if selected is empty. Lines keep their line
+// number so later credit notes can be checked against them.
+func creditLines(invoice Invoice, selected []CreditLine) ([]OrderLine, error) {
+	if len(selected) == 0 {
+		lines := make([]OrderLine, len(invoice.Lines))
+		for i, line := range invoice.Lines {
+			lines[i] = line
+			lines[i].UnitNetPrice = negAmount(line.UnitNetPrice)
+		}
+		return lines, nil
+	}
+	byNumber := map[int]OrderLine{}
+	for _, line := range invoice.Lines {
+		byNumber[line.LineNumber] = line
+	}
+	lines := make([]OrderLine, 0, len(selected))
+	for _, sel := range selected {
+		line, ok := byNumber[sel.LineNumber]
+		if !ok {
+			return nil, fmt.Errorf("booked invoice %d has no line %d", invoice.BookedInvoiceNumber, sel.LineNumber)
+		}
+		switch {
+		case sel.Quantity < 0 || sel.Amount.Sign() < 0:
+			return nil, fmt.Errorf("line %d: quantity and amount to credit must be positive", sel.LineNumber)
+		case sel.Quantity != 0 && !sel.Amount.IsZero():
+			return nil, fmt.Errorf("line %d: credit either a quantity or an amount, not both", sel.LineNumber)
+		case sel.Quantity != 0:
+			line.Quantity = sel.Quantity
+		case !sel.Amount.IsZero():
+			line.Quantity = 1
+			line.UnitNetPrice = sel.Amount.Ptr()
+			line.DiscountPercentage = 0
+		}
+		line.UnitNetPrice = negAmount(line.UnitNetPrice)
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func negAmount(a *Amount) *Amount {
+	if a == nil {
+		return nil
+	}
+	return a.Neg().Ptr()
+}
+
+// lineNetAmount is the net amount of a line, rounded to the minor unit of
+// currency.
+func lineNetAmount(line OrderLine, currency string) Amount {
+	if line.UnitNetPrice == nil {
+		return Amount{}
+	}
+	return line.UnitNetPrice.Mul(line.Quantity).Mul(1 - line.DiscountPercentage/100).Round(currency)
+}
+
+// checkCreditLimit returns ErrOverCredit if lines, together with the credit
+// notes (drafts and booked) already linked to invoice by ref, credit more of
+// a product and description than the invoice charged for it.
+func (client *Client) checkCreditLimit(ctx context.Context, invoice Invoice, ref string, lines []OrderLine) error {
+	filter := &Filter{}
+	filter.AndCondition("references.other", FilterOperatorEquals, ref)
+	if invoice.Customer != nil {
+		filter.AndCondition("customer.customerNumber", FilterOperatorEquals, invoice.Customer.CustomerNumber)
+	}
+	tc := &TypedClient[Invoice]{client: client}
+	credited := map[string]Amount{}
+	for _, class := range []string{"drafts", "booked"} {
+		invoices, err := tc.getEntities(ctx, "invoices/"+class, ListOptions{PageSize: invoicePageSize, Filter: filter})
+		if err != nil {
+			return err
+		}
+		for _, creditNote := range invoices {
+			if creditNote.NetAmount.Sign() >= 0 { // only credit notes count
+				continue
+			}
+			// collections leave out the lines, so fetch the credit note itself
+			if class == "drafts" {
+				creditNote, err = client.GetDraftInvoiceContext(ctx, creditNote.DraftInvoiceNumber)
+			} else {
+				creditNote, err = client.GetBookedInvoiceContext(ctx, creditNote.BookedInvoiceNumber)
+			}
+			if err != nil {
+				return err
+			}
+			for _, line := range creditNote.Lines {
+				credited[creditKey(line)] = credited[creditKey(line)].Sub(lineNetAmount(line, creditNote.Currency))
+			}
+		}
+	}
+	charged := map[string]Amount{}
+	for _, line := range invoice.Lines {
+		charged[creditKey(line)] = charged[creditKey(line)].Add(lineNetAmount(line, invoice.Currency))
+	}
+	for _, line := range lines {
+		key := creditKey(line)
+		credited[key] = credited[key].Sub(lineNetAmount(line, invoice.Currency))
+		if left := charged[key].Sub(credited[key]); left.Sign() < 0 {
+			return fmt.Errorf("%w: line %d (%s) of booked invoice %d would be credited %s, %s more than it charged",
+				ErrOverCredit, line.LineNumber, line.Description, invoice.BookedInvoiceNumber, credited[key], left.Neg())
+		}
+	}
+	return nil
+}
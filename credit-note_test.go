@@ -0,0 +1,93 @@
+package economic
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPartialCreditNotes(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("invoices/booked", Invoice{
+		BookedInvoiceNumber: 20,
+		Date:                MustParseDate("2024-03-01"),
+		Currency:            "DKK",
+		NetAmount:           MustParseAmount("1050"),
+		Layout:              &Layout{LayoutNumber: 19},
+		PaymentTerms:        &PaymentTerms{PaymentTermsNumber: 10},
+		Customer:            &Customer{CustomerNumber: 1},
+		Recipient:           &Recipient{Name: "Credit A/S", VatZone: VatZone{VatZoneNumber: 1}},
+		Notes:               &Notes{Heading: "March"},
+		References:          &References{Other: "sub-1"},
+		Lines: []OrderLine{
+			{LineNumber: 1, Description: "Seats", Product: &Product{ProductNumber: "seat"}, Quantity: 10, UnitNetPrice: MustParseAmount("100").Ptr()},
+			{LineNumber: 2, Description: "Setup", Quantity: 1, UnitNetPrice: MustParseAmount("50").Ptr()},
+		},
+	})
+
+	creditNote, err := client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{
+		Lines: []CreditLine{{LineNumber: 1, Quantity: 4}},
+		Note:  "4 seats returned",
+	})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if creditNote.NetAmount != MustParseAmount("-400") || len(creditNote.Lines) != 1 {
+		t.Fatalf("Expected a credit of 400, got %+v", creditNote)
+	}
+	if creditNote.Notes == nil || creditNote.Notes.Heading != "March" || creditNote.Notes.TextLine1 != "4 seats returned" {
+		t.Fatalf("Expected the note on the credit note, got %+v", creditNote.Notes)
+	}
+	if creditNote.References == nil || creditNote.References.Other != "sub-1 (invoice 20)" {
+		t.Fatalf("Expected the credit note to link to the invoice, got %+v", creditNote.References)
+	}
+	if _, err := client.BookInvoice(creditNote.DraftInvoiceNumber); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+
+	// credit notes for other invoices with the same reference do not count
+	srv.Add("invoices/booked", Invoice{
+		BookedInvoiceNumber: 21, Date: MustParseDate("2024-03-01"), Currency: "DKK", NetAmount: MustParseAmount("-500"),
+		Customer: &Customer{CustomerNumber: 1}, References: &References{Other: "sub-1"},
+		Lines: []OrderLine{{LineNumber: 1, Description: "Seats", Product: &Product{ProductNumber: "seat"}, Quantity: 5, UnitNetPrice: MustParseAmount("-100").Ptr()}},
+	})
+	// credit note lines are matched by product and description, not line number
+	srv.Add("invoices/booked", Invoice{
+		BookedInvoiceNumber: 22, Date: MustParseDate("2024-03-02"), Currency: "DKK", NetAmount: MustParseAmount("-100"),
+		Customer: &Customer{CustomerNumber: 1}, References: &References{Other: "sub-1 (invoice 20)"},
+		Lines: []OrderLine{{LineNumber: 7, Description: "Seats", Product: &Product{ProductNumber: "seat"}, Quantity: 1, UnitNetPrice: MustParseAmount("-100").Ptr()}},
+	})
+	if _, err := client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{Lines: []CreditLine{{LineNumber: 1, Quantity: 6}}}); !errors.Is(err, ErrOverCredit) {
+		t.Fatalf("Expected ErrOverCredit counting the renumbered credit note, got %v", err)
+	}
+	creditNote, err = client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{Lines: []CreditLine{{LineNumber: 1, Amount: MustParseAmount("500")}}})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if creditNote.NetAmount != MustParseAmount("-500") {
+		t.Fatalf("Expected a credit of 500, got %v", creditNote.NetAmount)
+	}
+	_, err = client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{Lines: []CreditLine{{LineNumber: 1, Quantity: 1}}})
+	if !errors.Is(err, ErrOverCredit) {
+		t.Fatalf("Expected ErrOverCredit for line 1, got %v", err)
+	}
+	if _, err := client.CreateCreditNoteForBookedInvoice(20); !errors.Is(err, ErrOverCredit) {
+		t.Fatalf("Expected ErrOverCredit for a full credit, got %v", err)
+	}
+	if _, err := client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{Lines: []CreditLine{{LineNumber: 2}}}); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{Lines: []CreditLine{{LineNumber: 3}}}); err == nil {
+		t.Fatalf("Expected an error for a missing line")
+	}
+	if _, err := client.CreateCreditNoteForBookedInvoice(20, CreditNoteOptions{AllowOverCredit: true}); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+}
+
+func TestCreditNoteWithoutRecipient(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("invoices/booked", Invoice{BookedInvoiceNumber: 21, Date: MustParseDate("2024-03-01"), Currency: "DKK", Customer: &Customer{CustomerNumber: 1}})
+	if _, err := client.CreateCreditNoteForBookedInvoice(21); err == nil {
+		t.Fatalf("Expected an error for an invoice without a recipient")
+	}
+}
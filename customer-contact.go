@@ -1,8 +1,8 @@
 package economic
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 )
 
@@ -10,9 +10,9 @@ func getCustomerContactsBaseUrl(customerNumber int) string {
 	return fmt.Sprintf("customers/%d/contacts", customerNumber)
 }
 
-func (client *Client) getAllCustomerContacts(customerNumber int) (contacts []CustomerContact, err error) {
+func (client *Client) getAllCustomerContacts(ctx context.Context, customerNumber int) (contacts []CustomerContact, err error) {
 	tc := &TypedClient[CustomerContact]{client: client}
-	contacts, err = tc.getEntities(getCustomerContactsBaseUrl(customerNumber), DEFAULT_PAGE_SIZE, "")
+	contacts, err = tc.getEntities(ctx, getCustomerContactsBaseUrl(customerNumber), ListOptions{})
 	return
 }
 
@@ -25,9 +25,12 @@ func (c *Customer) SetID(id int) {
 }
 
 func (client *Client) GetContactByEmail(customerNumber int, email string) (*CustomerContact, error) {
-	contacts, err := client.getAllCustomerContacts(customerNumber)
+	return client.GetContactByEmailContext(context.Background(), customerNumber, email)
+}
+
+func (client *Client) GetContactByEmailContext(ctx context.Context, customerNumber int, email string) (*CustomerContact, error) {
+	contacts, err := client.getAllCustomerContacts(ctx, customerNumber)
 	if err != nil {
-		log.Printf("Error: %s", err)
 		return nil, err
 	}
 	if len(contacts) == 0 {
@@ -42,7 +45,11 @@ func (client *Client) GetContactByEmail(customerNumber int, email string) (*Cust
 }
 
 func (client *Client) GetContactByName(customerNumber int, name string) (*CustomerContact, error) {
-	contacts, err := client.getAllCustomerContacts(customerNumber)
+	return client.GetContactByNameContext(context.Background(), customerNumber, name)
+}
+
+func (client *Client) GetContactByNameContext(ctx context.Context, customerNumber int, name string) (*CustomerContact, error) {
+	contacts, err := client.getAllCustomerContacts(ctx, customerNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +62,11 @@ func (client *Client) GetContactByName(customerNumber int, name string) (*Custom
 }
 
 func (client *Client) GetContactNumberByEmail(customerNumber int, email string) (int, error) {
-	contact, err := client.GetContactByEmail(customerNumber, email)
+	return client.GetContactNumberByEmailContext(context.Background(), customerNumber, email)
+}
+
+func (client *Client) GetContactNumberByEmailContext(ctx context.Context, customerNumber int, email string) (int, error) {
+	contact, err := client.GetContactByEmailContext(ctx, customerNumber, email)
 	if err != nil {
 		return 0, err
 	}
@@ -63,9 +74,12 @@ func (client *Client) GetContactNumberByEmail(customerNumber int, email string)
 }
 
 func (client *Client) UpdateOrCreateContact(customer Customer, contact *CustomerContact) error {
+	return client.UpdateOrCreateContactContext(context.Background(), customer, contact)
+}
+
+func (client *Client) UpdateOrCreateContactContext(ctx context.Context, customer Customer, contact *CustomerContact) error {
 	var customerInEconomic *Customer
-	fmt.Println("Update or create contact")
-	customerInEconomic, err := client.GetCustomer(customer)
+	customerInEconomic, err := client.GetCustomerContext(ctx, customer)
 	if err != nil {
 		return err
 	}
@@ -74,9 +88,8 @@ func (client *Client) UpdateOrCreateContact(customer Customer, contact *Customer
 	if contact == nil {
 		return nil
 	}
-	contacts, err := client.getAllCustomerContacts(customer.CustomerNumber)
+	contacts, err := client.getAllCustomerContacts(ctx, customer.CustomerNumber)
 	if err != nil {
-		log.Printf("Error: %s", err)
 		return err
 	}
 	// Check if contact already exists - search by email, phone or name
@@ -85,16 +98,15 @@ func (client *Client) UpdateOrCreateContact(customer Customer, contact *Customer
 			contact.CustomerContactNumber = c.CustomerContactNumber
 			customerId := customer.CustomerNumber
 			path := fmt.Sprintf("customers/%d/contacts/%d", customerId, contact.CustomerContactNumber)
-			err := client.callRestAPI(path, http.MethodPut, contact, &contact)
+			err := client.callRestAPI(ctx, path, http.MethodPut, contact, &contact)
 			if err != nil {
-				log.Printf("Error: %s", err)
+				client.logger().Error("error updating customer contact", "customerNumber", customerId, "customerContactNumber", contact.CustomerContactNumber, "error", err)
 			}
 			return nil
 		}
 	}
-	created, err := client.CreateCustomerContact(customer.CustomerNumber, *contact)
+	created, err := client.CreateCustomerContactContext(ctx, customer.CustomerNumber, *contact)
 	if err != nil {
-		log.Printf("Error: %s", err)
 		return err
 	}
 	*contact = created
@@ -102,15 +114,23 @@ func (client *Client) UpdateOrCreateContact(customer Customer, contact *Customer
 }
 
 func (client *Client) UpdateCustomerContact(customerNumber int, contact CustomerContact) (CustomerContact, error) {
+	return client.UpdateCustomerContactContext(context.Background(), customerNumber, contact)
+}
+
+func (client *Client) UpdateCustomerContactContext(ctx context.Context, customerNumber int, contact CustomerContact) (CustomerContact, error) {
 	var updatedContact CustomerContact
 	path := fmt.Sprintf("customers/%d/contacts/%d", customerNumber, contact.CustomerContactNumber)
-	err := client.callRestAPI(path, http.MethodPut, contact, &updatedContact)
+	err := client.callRestAPI(ctx, path, http.MethodPut, contact, &updatedContact)
 	return updatedContact, err
 }
 
 func (client *Client) CreateCustomerContact(customerNumber int, contact CustomerContact) (CustomerContact, error) {
+	return client.CreateCustomerContactContext(context.Background(), customerNumber, contact)
+}
+
+func (client *Client) CreateCustomerContactContext(ctx context.Context, customerNumber int, contact CustomerContact) (CustomerContact, error) {
 	var createdContact CustomerContact
-	err := client.callRestAPI(getCustomerContactsBaseUrl(customerNumber), http.MethodPost, contact, &createdContact)
+	err := client.callRestAPI(ctx, getCustomerContactsBaseUrl(customerNumber), http.MethodPost, contact, &createdContact)
 	if err != nil {
 		return createdContact, err
 	}
@@ -118,7 +138,11 @@ func (client *Client) CreateCustomerContact(customerNumber int, contact Customer
 }
 
 func (client *Client) GetCustomerContactNumber(customerNumber int) (int, error) {
-	contact, err := client.GetLastAddedCustomerContact(customerNumber)
+	return client.GetCustomerContactNumberContext(context.Background(), customerNumber)
+}
+
+func (client *Client) GetCustomerContactNumberContext(ctx context.Context, customerNumber int) (int, error) {
+	contact, err := client.GetLastAddedCustomerContactContext(ctx, customerNumber)
 	if err != nil {
 		return 0, err
 	}
@@ -126,8 +150,12 @@ func (client *Client) GetCustomerContactNumber(customerNumber int) (int, error)
 }
 
 func (client *Client) GetLastAddedCustomerContact(customerNumber int) (CustomerContact, error) {
+	return client.GetLastAddedCustomerContactContext(context.Background(), customerNumber)
+}
+
+func (client *Client) GetLastAddedCustomerContactContext(ctx context.Context, customerNumber int) (CustomerContact, error) {
 	var contact CustomerContact
-	contacts, err := client.getAllCustomerContacts(customerNumber)
+	contacts, err := client.getAllCustomerContacts(ctx, customerNumber)
 	if err != nil {
 		return contact, err
 	}
@@ -1,9 +1,12 @@
 package economic
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestUpdateOrCreateContact(t *testing.T) {
-	client := getTestClient()
+	client := getTestClient(t)
 	c := Customer{
 		Address: "Testvej 1",
 		City:    "Testby",
@@ -36,7 +39,7 @@ func TestUpdateOrCreateContact(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	contacts, err := client.getAllCustomerContacts(c.CustomerNumber)
+	contacts, err := client.getAllCustomerContacts(context.Background(), c.CustomerNumber)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
@@ -57,7 +60,7 @@ func TestUpdateOrCreateContact(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	contacts, err = client.getAllCustomerContacts(c.CustomerNumber)
+	contacts, err = client.getAllCustomerContacts(context.Background(), c.CustomerNumber)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
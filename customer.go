@@ -1,17 +1,21 @@
 package economic
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"math/rand"
 	"net/http"
-	"regexp"
+	"net/url"
 	"strconv"
 )
 
 func (client *Client) GetCustomerByNumber(number int) (*Customer, error) {
+	return client.GetCustomerByNumberContext(context.Background(), number)
+}
+
+func (client *Client) GetCustomerByNumberContext(ctx context.Context, number int) (*Customer, error) {
 	var customer Customer
-	err := client.callRestAPI(fmt.Sprintf("customers/%d", number), http.MethodGet, nil, &customer)
+	err := client.callRestAPI(ctx, fmt.Sprintf("customers/%d", number), http.MethodGet, nil, &customer)
 	return &customer, err
 }
 
@@ -24,65 +28,77 @@ func (client *Client) GetCustomer(customer *Customer) error {
 func generateRandomCustomNumber() int {
 	minimumCustumerNumber := int(1e8)
 	maximumCustumerNumber := int(1e9) - 1
-	newCustomNumber := rand.Intn(maximumCustumerNumber-minimumCustumerNumber) + minimumCustumerNumber
-	fmt.Printf("New custom number %d\n", newCustomNumber)
-	return newCustomNumber
+	return rand.Intn(maximumCustumerNumber-minimumCustumerNumber) + minimumCustumerNumber
 }
 
 func (client *Client) CreateCustomer(customer *Customer, contact *CustomerContact) (*Customer, error) {
+	return client.CreateCustomerContext(context.Background(), customer, contact)
+}
+
+func (client *Client) CreateCustomerContext(ctx context.Context, customer *Customer, contact *CustomerContact) (*Customer, error) {
 	if customer == nil {
 		return nil, fmt.Errorf("No customer created")
 	}
 	if customer.CustomerNumber == 0 {
 		customer.CustomerNumber = generateRandomCustomNumber()
-		return client.CreateCustomer(customer, contact)
+		return client.CreateCustomerContext(ctx, customer, contact)
 	}
 	r := Customer{}
-	err := client.callRestAPI("customers", http.MethodPost, customer, &r)
+	err := client.callRestAPI(ctx, "customers", http.MethodPost, customer, &r)
 	if err != nil {
-		fmt.Printf("Error creating customer %+v %s\n", *customer, err.Error())
+		client.logger().Error("error creating customer", "customerNumber", customer.CustomerNumber, "error", err)
 		return &r, err
 	}
 	if contact == nil {
 		return &r, err
 	}
-	err = client.UpdateOrCreateContact(r, contact)
+	err = client.UpdateOrCreateContactContext(ctx, r, contact)
 	return &r, err
 }
 
 func (client *Client) SetEInvoicing(customerNumber int, disableEInvoicing bool) error {
+	return client.SetEInvoicingContext(context.Background(), customerNumber, disableEInvoicing)
+}
+
+func (client *Client) SetEInvoicingContext(ctx context.Context, customerNumber int, disableEInvoicing bool) error {
 	body := []map[string]any{{
 		"op":    "replace",
 		"path":  "/eInvoicingDisabledByDefault",
 		"value": disableEInvoicing,
 	}}
-	return client.callRestAPI(fmt.Sprintf("customers/%d", customerNumber), http.MethodPatch, body, nil)
+	return client.callRestAPI(ctx, fmt.Sprintf("customers/%d", customerNumber), http.MethodPatch, body, nil)
 }
 
 func (client *Client) UpdateCustomer(customer *Customer, contact *CustomerContact) (int, error) {
+	return client.UpdateCustomerContext(context.Background(), customer, contact)
+}
+
+func (client *Client) UpdateCustomerContext(ctx context.Context, customer *Customer, contact *CustomerContact) (int, error) {
 	if customer == nil {
 		return 0, nil
 	}
-	err := client.callRestAPI(fmt.Sprintf("customers/%d", customer.CustomerNumber), http.MethodPut, customer, nil)
+	err := client.callRestAPI(ctx, fmt.Sprintf("customers/%d", customer.CustomerNumber), http.MethodPut, customer, nil)
 	if err != nil {
 		return 0, err
 	}
 	if contact == nil {
 		return customer.CustomerNumber, nil
 	}
-	err = client.UpdateOrCreateContact(*customer, contact)
+	err = client.UpdateOrCreateContactContext(ctx, *customer, contact)
 	return customer.CustomerNumber, err
 }
 
 func (client *Client) DeleteCustomer(customer *Customer) error {
-	err := client.callRestAPI(fmt.Sprintf("customers/%d", customer.CustomerNumber), http.MethodDelete, nil, nil)
+	return client.DeleteCustomerContext(context.Background(), customer)
+}
+
+func (client *Client) DeleteCustomerContext(ctx context.Context, customer *Customer) error {
+	err := client.callRestAPI(ctx, fmt.Sprintf("customers/%d", customer.CustomerNumber), http.MethodDelete, nil, nil)
 	return err
 }
 
 func getRightCustomerFromList(customers []Customer) Customer {
-	fmt.Printf("list customers %+v\n", customers)
 	if len(customers) > 1 {
-		fmt.Printf("multiple customers found with org number %s", customers[0].CorporateIdentificationNumber)
 		for _, customer := range customers {
 			if customer.CorporateIdentificationNumber == strconv.Itoa(customer.CustomerNumber) {
 				return customer
@@ -100,22 +116,26 @@ func NormalizeCorporateId(id string) string {
 }
 
 func (client *Client) GetCustomer(customer Customer) (*Customer, error) {
-	customerInEconomic, _ := client.GetCustomerByNumber(customer.CustomerNumber)
-	fmt.Printf("customer in E-co %+v\n", customerInEconomic)
+	return client.GetCustomerContext(context.Background(), customer)
+}
+
+func (client *Client) GetCustomerContext(ctx context.Context, customer Customer) (*Customer, error) {
+	logger := client.logger().With("customerNumber", customer.CustomerNumber)
+	customerInEconomic, _ := client.GetCustomerByNumberContext(ctx, customer.CustomerNumber)
 	if customerInEconomic.CustomerNumber != 0 && NormalizeCorporateId(customerInEconomic.CorporateIdentificationNumber) != customer.CorporateIdentificationNumber {
-		customers := client.FindCustomerByOrgNumber(customer.CorporateIdentificationNumber)
-		fmt.Printf("customers by org number %+v\n", customers)
+		customers := client.FindCustomerByOrgNumberContext(ctx, customer.CorporateIdentificationNumber)
+		logger.Debug("customer number taken by another company, searched by corporate id", "found", len(customers))
 		if len(customers) == 0 {
 			// maybe the customer did not have a corporate identification number in E-co:
 			// this can return a different customer, so one needs to handle this in the main application's logic
 			if customerInEconomic.CorporateIdentificationNumber == "" && strconv.Itoa(customerInEconomic.CustomerNumber) == customer.CorporateIdentificationNumber {
-				fmt.Printf("Matching by customer number, but missing corporate id number for customer in E-co %+v\n", customerInEconomic)
+				logger.Warn("matching by customer number, but the customer in e-conomic has no corporate id number")
 				return customerInEconomic, nil
 			}
 			return nil, nil
 		}
 		rightCustomer := getRightCustomerFromList(customers)
-		fmt.Printf("right customer %+v\n", rightCustomer)
+		logger.Debug("found customer by corporate id", "foundCustomerNumber", rightCustomer.CustomerNumber)
 		return &rightCustomer, nil
 	} else if customerInEconomic.CustomerNumber == 0 {
 		return nil, nil
@@ -124,9 +144,8 @@ func (client *Client) GetCustomer(customer Customer) (*Customer, error) {
 	}
 }
 
-func entityAlreadyInEconomic(message string) bool {
-	re := regexp.MustCompile("\"errorCode\": \"E06010\"") // response is not JSON, so we're using regex here
-	return re.MatchString(message)
+func entityAlreadyInEconomic(err error) bool {
+	return HasErrorCode(err, ErrorCodeAlreadyExists)
 }
 
 const MAX_NUMBER_CREATE_CUSTOMER_ATTEMPTS = 10
@@ -135,28 +154,31 @@ const MAX_NUMBER_CREATE_CUSTOMER_ATTEMPTS = 10
 // customer does not exist, it creates a new customer in economic using the
 // provided.  `customer` is read and modified in-place.
 func (client *Client) GetOrCreateCustomer(customer *Customer, contact *CustomerContact, count int) (*Customer, error) {
+	return client.GetOrCreateCustomerContext(context.Background(), customer, contact, count)
+}
+
+func (client *Client) GetOrCreateCustomerContext(ctx context.Context, customer *Customer, contact *CustomerContact, count int) (*Customer, error) {
 	if customer.CorporateIdentificationNumber != "" {
 		customer.VatNumber = customer.CorporateIdentificationNumber
 	}
-	fmt.Printf("count %d\n", count)
-	customerInEconomic, err := client.GetCustomer(*customer)
-	fmt.Printf("c in e-co %+v\n", customerInEconomic)
+	logger := client.logger().With("customerNumber", customer.CustomerNumber, "attempt", count)
+	customerInEconomic, err := client.GetCustomerContext(ctx, *customer)
 	if err != nil {
-		fmt.Printf("Error getting customer %d\n", customer.CustomerNumber)
+		logger.Error("error getting customer", "error", err)
 		return nil, err
 	}
 
 	if count > MAX_NUMBER_CREATE_CUSTOMER_ATTEMPTS {
-		fmt.Printf("Exceeded the maximum number of attempts to create a customer %+v\n", customer)
+		logger.Error("exceeded the maximum number of attempts to create a customer")
 		return nil, fmt.Errorf("Exceeded the maximum number of attempts to create a customer\n")
 	}
 	if customerInEconomic == nil {
-		customer, err = client.CreateCustomer(customer, contact)
-		if err != nil && entityAlreadyInEconomic(err.Error()) {
+		customer, err = client.CreateCustomerContext(ctx, customer, contact)
+		if err != nil && entityAlreadyInEconomic(err) {
 			count++
-			fmt.Printf("Warning: (this should not be possible) Customer with customer number %d already exists\n", customer.CustomerNumber)
+			logger.Warn("(this should not be possible) customer number already exists")
 			customer.CustomerNumber = generateRandomCustomNumber()
-			return client.GetOrCreateCustomer(customer, contact, count)
+			return client.GetOrCreateCustomerContext(ctx, customer, contact, count)
 		}
 		if err != nil {
 			return nil, err
@@ -164,34 +186,41 @@ func (client *Client) GetOrCreateCustomer(customer *Customer, contact *CustomerC
 	}
 	foundDifferentCustomerInEconomic := customerInEconomic != nil && customerInEconomic.CorporateIdentificationNumber != customer.CorporateIdentificationNumber && customerInEconomic.VatNumber != customer.VatNumber
 	if foundDifferentCustomerInEconomic {
-		fmt.Printf("Customer with customer number %d already exists\n", customer.CustomerNumber)
+		logger.Info("customer number already used by a different customer")
 		customer.CustomerNumber = generateRandomCustomNumber()
 		count++
-		return client.GetOrCreateCustomer(customer, contact, count)
+		return client.GetOrCreateCustomerContext(ctx, customer, contact, count)
 	}
 
-	return customer, client.UpdateOrCreateContact(*customer, contact)
+	return customer, client.UpdateOrCreateContactContext(ctx, *customer, contact)
 }
 
 func (client *Client) UpdateOrCreateCustomer(customer Customer, contact CustomerContact) (int, error) {
-	fmt.Println("Update or create customer")
-	customerInEconomic, err := client.GetOrCreateCustomer(&customer, &contact, 1)
+	return client.UpdateOrCreateCustomerContext(context.Background(), customer, contact)
+}
+
+func (client *Client) UpdateOrCreateCustomerContext(ctx context.Context, customer Customer, contact CustomerContact) (int, error) {
+	customerInEconomic, err := client.GetOrCreateCustomerContext(ctx, &customer, &contact, 1)
 	if err != nil {
 		return 0, err
 	}
-	return client.UpdateCustomer(customerInEconomic, &contact)
+	return client.UpdateCustomerContext(ctx, customerInEconomic, &contact)
 }
 
 func (client *Client) FindCustomerByOrgNumber(org string) []Customer {
+	return client.FindCustomerByOrgNumberContext(context.Background(), org)
+}
+
+func (client *Client) FindCustomerByOrgNumberContext(ctx context.Context, org string) []Customer {
 	if org == "" {
 		return nil
 	}
 	filter := &Filter{}
 	filter.AndCondition("corporateIdentificationNumber", FilterOperatorEquals, org)
 	resp := CollectionReponse[Customer]{}
-	err := client.callRestAPI("customers?filter="+filter.String(), http.MethodGet, nil, &resp)
+	err := client.callRestAPI(ctx, "customers?filter="+url.QueryEscape(filter.String()), http.MethodGet, nil, &resp)
 	if err != nil {
-		log.Printf("Error: %s", err)
+		client.logger().Error("error finding customer by corporate id", "error", err)
 	}
 	return resp.Collection
 }
@@ -207,12 +236,12 @@ type Customer struct {
 	// Optional fields
 	Barred                        bool         `json:"barred,omitempty"`                        // Boolean indication of whether the customer is barred from invoicing.
 	Address                       string       `json:"address,omitempty"`                       // Address for the customer including street and number.
-	Balance                       float64      `json:"balance,omitempty"`                       // The outstanding amount for this customer.
+	Balance                       *Amount      `json:"balance,omitempty"`                       // The outstanding amount for this customer.
 	CorporateIdentificationNumber string       `json:"corporateIdentificationNumber,omitempty"` // Corporate Identification Number. For example CVR in Denmark.
 	PNumber                       string       `json:"pNumber,omitempty"`                       // Extension of corporate identification number (CVR). Identifying separate production unit (p-nummer).
 	City                          string       `json:"city,omitempty"`                          // The customer's city.
 	Country                       string       `json:"country,omitempty"`                       // The customer's country.
-	CreditLimit                   float64      `json:"creditLimit,omitempty"`                   // A maximum credit for this customer. Once the maximum is reached or passed in connection with an order/quotation/invoice for this customer you see a warning in e-conomic.
+	CreditLimit                   *Amount      `json:"creditLimit,omitempty"`                   // A maximum credit for this customer. Once the maximum is reached or passed in connection with an order/quotation/invoice for this customer you see a warning in e-conomic.
 	CustomerNumber                int          `json:"customerNumber,omitempty"`                // The customer number is a positive unique numerical identifier with a maximum of 9 digits. If no customer number is specified a number will be supplied by the system.
 	EAN                           string       `json:"ean,omitempty"`                           // European Article Number. EAN is used for invoicing the Danish public sector.
 	Email                         string       `json:"email,omitempty"`                         // Customer e-mail address where e-conomic invoices should be emailed. Note: you can specify multiple email addresses in this field, separated by a space. If you need to send a copy of the invoice or write to other e-mail addresses, you can also create one or more customer contacts.
@@ -231,6 +260,7 @@ type Customer struct {
 // CustomerGroup represents a customer group.
 type CustomerGroup struct {
 	CustomerGroupNumber int    `json:"customerGroupNumber"` // The unique identifier of the customer group.
+	Name                string `json:"name,omitempty"`      // The name of the customer group.
 	Self                string `json:"self,omitempty"`      // A unique link reference to the customer group item.
 }
 
@@ -1,6 +1,7 @@
 package economic
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"testing"
@@ -8,14 +9,19 @@ import (
 
 func TestWhoami(t *testing.T) {
 	resp := map[string]any{}
-	client := getTestClient()
-	err := client.callRestAPI("/self", http.MethodGet, nil, &resp)
+	client := getTestClient(t)
+	err := client.callRestAPI(context.Background(), "/self", http.MethodGet, nil, &resp)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	log.Printf("agreementNumber: %d", int(resp["agreementNumber"].(float64)))
-	log.Printf("application.AppNumber: %d", int(resp["application"].(map[string]any)["appNumber"].(float64)))
-	t.Fatalf("%v", resp)
+	agreementNumber, _ := resp["agreementNumber"].(float64)
+	application, _ := resp["application"].(map[string]any)
+	appNumber, _ := application["appNumber"].(float64)
+	if agreementNumber == 0 || appNumber == 0 {
+		t.Fatalf("%v", resp)
+	}
+	log.Printf("agreementNumber: %d", int(agreementNumber))
+	log.Printf("application.AppNumber: %d", int(appNumber))
 }
 
 func TestFindCustomerByName(t *testing.T) {
@@ -38,7 +44,7 @@ func TestFindCustomerByName(t *testing.T) {
 		CorporateIdentificationNumber: "28971958",
 	}
 
-	client := getTestClient()
+	client := getTestClient(t)
 	client.CreateCustomer(&c, nil)
 	defer client.DeleteCustomer(&c)
 	found := client.FindCustomerByOrgNumber("28971958")
@@ -74,7 +80,7 @@ func TestGetOrCreateCustomer(t *testing.T) {
 		Name:  "Abe Testesen",
 		Email: "jungle@abe.com",
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	_, err := client.GetOrCreateCustomer(c, &contact, 1)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
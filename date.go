@@ -0,0 +1,149 @@
+package economic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without a time or time zone, such as an invoice
+// date. It marshals to and from e-conomic's YYYY-MM-DD; the zero Date
+// marshals as null.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// ParseDate parses a YYYY-MM-DD date. A timestamp such as
+// 2024-03-01T10:00:00Z is also accepted and its date taken as it is written.
+func ParseDate(s string) (Date, error) {
+	if len(s) > len(dateLayout) && s[len(dateLayout)] == 'T' {
+		if _, err := time.Parse(time.RFC3339, s); err == nil {
+			s = s[:len(dateLayout)]
+		}
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return DateOf(t), nil
+}
+
+// MustParseDate is ParseDate for constants; it panics on an error.
+func MustParseDate(s string) Date {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDate returns the date, normalizing e.g. March 0 to the last day of
+// February as time.Date does.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// Today returns the current date in loc, or in the local time zone if loc is
+// nil.
+func Today(loc *time.Location) Date {
+	if loc == nil {
+		loc = time.Local
+	}
+	return DateOf(time.Now().In(loc))
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight at the start of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Compare returns -1, 0 or 1 as d is before, equal to or after other.
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return cmpInt(d.Year, other.Year)
+	case d.Month != other.Month:
+		return cmpInt(int(d.Month), int(other.Month))
+	}
+	return cmpInt(d.Day, other.Day)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (d Date) Before(other Date) bool { return d.Compare(other) < 0 }
+func (d Date) After(other Date) bool  { return d.Compare(other) > 0 }
+
+func (d Date) AddDays(days int) Date {
+	return NewDate(d.Year, d.Month, d.Day+days)
+}
+
+// Ptr returns a pointer to a copy of d, for the optional dates on Order.
+func (d Date) Ptr() *Date {
+	return &d
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date %s: %w", data, err)
+	}
+	return d.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,70 @@
+package economic
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2024-03-01")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if d != NewDate(2024, time.March, 1) || d.String() != "2024-03-01" {
+		t.Fatalf("Expected 2024-03-01, got %s", d)
+	}
+	if d, err := ParseDate("2024-03-01T23:30:00+02:00"); err != nil || d != NewDate(2024, 3, 1) {
+		t.Fatalf("Expected the date of the timestamp, got %s (%v)", d, err)
+	}
+	for _, in := range []string{"", "2024-3-1", "2024-02-30", "01-03-2024", "2024-03-01T"} {
+		if _, err := ParseDate(in); err == nil {
+			t.Errorf("Expected ParseDate(%q) to fail", in)
+		}
+	}
+	if ValidateDate("2024-13-01") {
+		t.Fatalf("Expected month 13 to be invalid")
+	}
+}
+
+func TestDateArithmetic(t *testing.T) {
+	d := NewDate(2024, 2, 28)
+	if next := d.AddDays(1); next != NewDate(2024, 2, 29) {
+		t.Fatalf("Expected 2024-02-29, got %s", next)
+	}
+	if next := d.AddDays(2); next != NewDate(2024, 3, 1) || !next.After(d) || next.Before(d) {
+		t.Fatalf("Expected 2024-03-01 after %s, got %s", d, next)
+	}
+	if NewDate(2023, 12, 31).Compare(NewDate(2024, 1, 1)) != -1 {
+		t.Fatalf("Expected 2023-12-31 before 2024-01-01")
+	}
+	loc := time.FixedZone("CET", 3600)
+	if got := DateOf(d.In(loc)); got != d {
+		t.Fatalf("Expected %s, got %s", d, got)
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	var v struct {
+		Date    Date  `json:"date"`
+		DueDate *Date `json:"dueDate,omitempty"`
+		Empty   Date  `json:"empty"`
+	}
+	if err := json.Unmarshal([]byte(`{"date": "2024-03-01", "dueDate": "2024-03-09", "empty": ""}`), &v); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if v.Date != NewDate(2024, 3, 1) || *v.DueDate != NewDate(2024, 3, 9) || !v.Empty.IsZero() {
+		t.Fatalf("Unexpected dates %s, %s, %s", v.Date, v.DueDate, v.Empty)
+	}
+	v.DueDate = nil
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if string(data) != `{"date":"2024-03-01","empty":null}` {
+		t.Fatalf("Unexpected JSON %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"date": 20240301}`), &v); err == nil {
+		t.Fatalf("Expected an error for a numeric date")
+	}
+}
@@ -1,9 +1,9 @@
 package economic
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"strings"
 )
 
 const DIMENSIONAPI_BASE = "/dimensionsapi/v5.3.0"
@@ -17,46 +17,60 @@ type dimension struct {
 }
 
 func (client *Client) CreateDimensionValue(number, key int, name string) error {
+	return client.CreateDimensionValueContext(context.Background(), number, key, name)
+}
+
+func (client *Client) CreateDimensionValueContext(ctx context.Context, number, key int, name string) error {
 	body := dimension{
 		Active:          true,
 		DimensionNumber: number,
 		Key:             key,
 		Name:            name,
 	}
-	return client.callAPI(DIMENSIONAPI_BASE+"/values", http.MethodPost, nil, body, nil)
+	return client.callAPI(ctx, DIMENSIONAPI_BASE+"/values", http.MethodPost, nil, body, nil)
 }
 
 // Creates dimension value if doesn't exist.
 // Returns true if the value was created, or false if it already exists.
 // Name is not changed/updated if the value already exists.
 func (client *Client) CreateDimensionValueIfItDoesNotExist(number, key int, name string) (bool, error) {
-	err := client.callAPI(fmt.Sprintf(DIMENSIONAPI_BASE+"/values/%d/%d", number, key), http.MethodGet, nil, nil, nil)
+	return client.CreateDimensionValueIfItDoesNotExistContext(context.Background(), number, key, name)
+}
+
+func (client *Client) CreateDimensionValueIfItDoesNotExistContext(ctx context.Context, number, key int, name string) (bool, error) {
+	err := client.callAPI(ctx, fmt.Sprintf(DIMENSIONAPI_BASE+"/values/%d/%d", number, key), http.MethodGet, nil, nil, nil)
 	if err == nil {
 		return false, nil
 	}
-	// XXX TODO nicer 404 handling
-	if !strings.Contains(err.Error(), "not found") {
+	if !IsNotFound(err) {
 		return false, err
 	}
-	return true, client.CreateDimensionValue(number, key, name)
+	return true, client.CreateDimensionValueContext(ctx, number, key, name)
 }
 
 // Updates or creates a dimension value.
 // Returns true if the value was created, or false if it already exists.
 func (client *Client) CreateOrUpdateDimensionValue(number, key int, name string) (bool, error) {
+	return client.CreateOrUpdateDimensionValueContext(context.Background(), number, key, name)
+}
+
+func (client *Client) CreateOrUpdateDimensionValueContext(ctx context.Context, number, key int, name string) (bool, error) {
 	var existingDimension dimension
-	err := client.callAPI(fmt.Sprintf(DIMENSIONAPI_BASE+"/values/%d/%d", number, key), http.MethodGet, nil, nil, &existingDimension)
+	err := client.callAPI(ctx, fmt.Sprintf(DIMENSIONAPI_BASE+"/values/%d/%d", number, key), http.MethodGet, nil, nil, &existingDimension)
 	if err == nil {
-		return client.UpdateDimensionValue(number, key, name, existingDimension.ObjectVersion)
+		return client.UpdateDimensionValueContext(ctx, number, key, name, existingDimension.ObjectVersion)
 	}
-	// XXX TODO nicer 404 handling
-	if !strings.Contains(err.Error(), "not found") {
+	if !IsNotFound(err) {
 		return false, err
 	}
-	return true, client.CreateDimensionValue(number, key, name)
+	return true, client.CreateDimensionValueContext(ctx, number, key, name)
 }
 
 func (client *Client) UpdateDimensionValue(number, key int, name, objectVersion string) (bool, error) {
+	return client.UpdateDimensionValueContext(context.Background(), number, key, name, objectVersion)
+}
+
+func (client *Client) UpdateDimensionValueContext(ctx context.Context, number, key int, name, objectVersion string) (bool, error) {
 	body := dimension{
 		Active:          true,
 		DimensionNumber: number,
@@ -64,14 +78,18 @@ func (client *Client) UpdateDimensionValue(number, key int, name, objectVersion
 		Name:            name,
 		ObjectVersion:   objectVersion,
 	}
-	return false, client.callAPI(DIMENSIONAPI_BASE+"/values", http.MethodPut, nil, body, nil)
+	return false, client.callAPI(ctx, DIMENSIONAPI_BASE+"/values", http.MethodPut, nil, body, nil)
 }
 func (client *Client) AddDimensionValueToDraftEntry(dimensionNumber, dimensionKey, journalNumber, entryNumber int) error {
+	return client.AddDimensionValueToDraftEntryContext(context.Background(), dimensionNumber, dimensionKey, journalNumber, entryNumber)
+}
+
+func (client *Client) AddDimensionValueToDraftEntryContext(ctx context.Context, dimensionNumber, dimensionKey, journalNumber, entryNumber int) error {
 	body := map[string]any{
 		"dimensionNumber": dimensionNumber,
 		"dimensionKey":    dimensionKey,
 		"journalNumber":   journalNumber,
 		"entryNumber":     entryNumber,
 	}
-	return client.callAPI(fmt.Sprintf(DIMENSIONAPI_BASE+"/dimension-data/draft-entries"), http.MethodPost, nil, body, nil)
+	return client.callAPI(ctx, fmt.Sprintf(DIMENSIONAPI_BASE+"/dimension-data/draft-entries"), http.MethodPost, nil, body, nil)
 }
@@ -0,0 +1,151 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// draftBody returns the writable part of a draft invoice in the shape
+// invoices/drafts accepts. Invoice also holds read-only properties, such as
+// the amounts, remainder and pdf, and flattened references from the OpenAPI
+// that e-conomic rejects or ignores on a PUT.
+func (invoice *Invoice) draftBody() *Order {
+	order := &Order{
+		Date:       invoice.Date,
+		Currency:   invoice.Currency,
+		Delivery:   invoice.Delivery,
+		Notes:      invoice.Notes,
+		References: invoice.References,
+		Lines:      invoice.Lines,
+		ExternalId: invoice.ExternalId,
+		Project:    invoice.Project,
+	}
+	if invoice.ExchangeRate != 0 {
+		order.ExchangeRate = &invoice.ExchangeRate
+	}
+	if !invoice.DueDate.IsZero() {
+		order.DueDate = invoice.DueDate.Ptr()
+	}
+	if invoice.Layout != nil {
+		order.Layout = Layout{LayoutNumber: invoice.Layout.LayoutNumber}
+	}
+	if invoice.PaymentTerms != nil {
+		order.PaymentTerms = PaymentTerms{PaymentTermsNumber: invoice.PaymentTerms.PaymentTermsNumber}
+	} else {
+		order.PaymentTerms.PaymentTermsNumber = invoice.PaymentTermsNumber
+	}
+	if invoice.Customer != nil {
+		order.Customer.CustomerNumber = invoice.Customer.CustomerNumber
+	} else {
+		order.Customer.CustomerNumber = invoice.CustomerNumber
+	}
+	if invoice.Recipient != nil {
+		order.Recipient = *invoice.Recipient
+	}
+	if order.Project == nil && invoice.ProjectNumber != 0 {
+		order.Project = &Project{ProjectNumber: invoice.ProjectNumber}
+	}
+	if order.Lines == nil {
+		order.Lines = []OrderLine{}
+	}
+	return order
+}
+
+func (client *Client) UpdateDraftInvoice(invoice *Invoice) (Invoice, error) {
+	return client.UpdateDraftInvoiceContext(context.Background(), invoice)
+}
+
+// UpdateDraftInvoiceContext replaces the draft with the same
+// DraftInvoiceNumber, keeping its number. Read-only properties of invoice are
+// not sent, so a draft fetched with GetDraftInvoice can be changed and passed
+// back as it is.
+func (client *Client) UpdateDraftInvoiceContext(ctx context.Context, invoice *Invoice) (updated Invoice, err error) {
+	if invoice.DraftInvoiceNumber == 0 {
+		return updated, fmt.Errorf("cannot update a draft invoice without a draft invoice number")
+	}
+	err = client.callRestAPI(ctx, fmt.Sprintf("invoices/drafts/%d", invoice.DraftInvoiceNumber), http.MethodPut, invoice.draftBody(), &updated)
+	return
+}
+
+func (client *Client) AddDraftInvoiceLines(invoiceNo int, lines ...OrderLine) (Invoice, error) {
+	return client.AddDraftInvoiceLinesContext(context.Background(), invoiceNo, lines...)
+}
+
+// AddDraftInvoiceLinesContext appends lines to a draft invoice with a POST
+// to its lines, leaving the rest of the draft alone. Lines without a
+// LineNumber are numbered after the existing ones by e-conomic. It returns the
+// draft as it is after the lines were added.
+func (client *Client) AddDraftInvoiceLinesContext(ctx context.Context, invoiceNo int, lines ...OrderLine) (Invoice, error) {
+	body := struct {
+		Lines []OrderLine `json:"lines"`
+	}{lines}
+	if err := client.callRestAPI(ctx, fmt.Sprintf("invoices/drafts/%d/lines", invoiceNo), http.MethodPost, body, nil); err != nil {
+		return Invoice{}, err
+	}
+	return client.GetDraftInvoiceContext(ctx, invoiceNo)
+}
+
+func (client *Client) ReplaceDraftInvoiceLine(invoiceNo int, line OrderLine) (Invoice, error) {
+	return client.ReplaceDraftInvoiceLineContext(context.Background(), invoiceNo, line)
+}
+
+// ReplaceDraftInvoiceLineContext replaces the line with the same LineNumber.
+// It rewrites the whole draft; see editDraftInvoiceLines.
+func (client *Client) ReplaceDraftInvoiceLineContext(ctx context.Context, invoiceNo int, line OrderLine) (Invoice, error) {
+	return client.editDraftInvoiceLines(ctx, invoiceNo, func(existing []OrderLine) ([]OrderLine, error) {
+		for i := range existing {
+			if existing[i].LineNumber == line.LineNumber {
+				existing[i] = line
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("draft invoice %d has no line %d", invoiceNo, line.LineNumber)
+	})
+}
+
+func (client *Client) RemoveDraftInvoiceLines(invoiceNo int, lineNumbers ...int) (Invoice, error) {
+	return client.RemoveDraftInvoiceLinesContext(context.Background(), invoiceNo, lineNumbers...)
+}
+
+// RemoveDraftInvoiceLinesContext removes the lines with the given numbers.
+// The remaining lines keep their numbers. It rewrites the whole draft; see
+// editDraftInvoiceLines.
+func (client *Client) RemoveDraftInvoiceLinesContext(ctx context.Context, invoiceNo int, lineNumbers ...int) (Invoice, error) {
+	return client.editDraftInvoiceLines(ctx, invoiceNo, func(existing []OrderLine) ([]OrderLine, error) {
+		remove := map[int]bool{}
+		for _, n := range lineNumbers {
+			remove[n] = true
+		}
+		kept := []OrderLine{}
+		for _, line := range existing {
+			if remove[line.LineNumber] {
+				delete(remove, line.LineNumber)
+				continue
+			}
+			kept = append(kept, line)
+		}
+		for n := range remove {
+			return nil, fmt.Errorf("draft invoice %d has no line %d", invoiceNo, n)
+		}
+		return kept, nil
+	})
+}
+
+// editDraftInvoiceLines reads the draft, lets edit change its lines and
+// writes the whole draft back, as e-conomic has no endpoint to change or
+// remove a single line. It is not atomic: changes made to the draft by others
+// between the read and the write are lost, so edit a draft from one place at
+// a time.
+func (client *Client) editDraftInvoiceLines(ctx context.Context, invoiceNo int, edit func([]OrderLine) ([]OrderLine, error)) (Invoice, error) {
+	draft, err := client.GetDraftInvoiceContext(ctx, invoiceNo)
+	if err != nil {
+		return draft, err
+	}
+	lines, err := edit(append([]OrderLine(nil), draft.Lines...))
+	if err != nil {
+		return draft, err
+	}
+	draft.Lines = lines
+	return client.UpdateDraftInvoiceContext(ctx, &draft)
+}
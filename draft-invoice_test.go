@@ -0,0 +1,59 @@
+package economic
+
+import (
+	"testing"
+)
+
+func TestUpdateDraftInvoice(t *testing.T) {
+	client := getTestClient(t)
+	order := testOrder("edit-1")
+	order.Project = &Project{ProjectNumber: 3}
+	order.Lines = []OrderLine{{LineNumber: 1, Description: "Hours", Quantity: 2, UnitNetPrice: MustParseAmount("100").Ptr()}}
+	draft, err := client.CreateInvoice(order)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+
+	draft.Notes = &Notes{Heading: "Changed"}
+	updated, err := client.UpdateDraftInvoice(&draft)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if updated.DraftInvoiceNumber != draft.DraftInvoiceNumber || updated.Notes == nil || updated.Notes.Heading != "Changed" {
+		t.Fatalf("Expected draft %d to be updated in place, got %+v", draft.DraftInvoiceNumber, updated)
+	}
+	if updated.Project == nil || updated.Project.ProjectNumber != 3 {
+		t.Fatalf("Expected the project to be kept, got %+v", updated.Project)
+	}
+
+	updated, err = client.AddDraftInvoiceLines(draft.DraftInvoiceNumber, OrderLine{Description: "Travel", Quantity: 1, UnitNetPrice: MustParseAmount("50").Ptr()})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(updated.Lines) != 2 || updated.Lines[1].LineNumber != 2 || updated.NetAmount != MustParseAmount("250") {
+		t.Fatalf("Expected a second line, got %+v", updated)
+	}
+	if _, err := client.AddDraftInvoiceLines(999999, OrderLine{Description: "Travel", Quantity: 1}); !IsNotFound(err) {
+		t.Fatalf("Expected not found adding lines to a missing draft, got %v", err)
+	}
+	updated, err = client.ReplaceDraftInvoiceLine(draft.DraftInvoiceNumber, OrderLine{LineNumber: 1, Description: "Hours", Quantity: 3, UnitNetPrice: MustParseAmount("100").Ptr()})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if updated.NetAmount != MustParseAmount("350") {
+		t.Fatalf("Expected net amount 350, got %v", updated.NetAmount)
+	}
+	updated, err = client.RemoveDraftInvoiceLines(draft.DraftInvoiceNumber, 2)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(updated.Lines) != 1 || updated.Lines[0].LineNumber != 1 || updated.NetAmount != MustParseAmount("300") {
+		t.Fatalf("Expected only line 1, got %+v", updated)
+	}
+	if _, err := client.RemoveDraftInvoiceLines(draft.DraftInvoiceNumber, 9); err == nil {
+		t.Fatalf("Expected an error removing a missing line")
+	}
+	if updated.DraftInvoiceNumber != draft.DraftInvoiceNumber {
+		t.Fatalf("Expected the draft number to be kept, got %d", updated.DraftInvoiceNumber)
+	}
+}
@@ -1,14 +1,27 @@
 package economic
 
 type Layout struct {
-	LayoutNumber int    `json:"layoutNumber"`   //A unique identifier of the layout."`
-	Self         string `json:"self,omitempty"` //A unique reference to the layout resource."`
+	LayoutNumber int    `json:"layoutNumber"`      //A unique identifier of the layout."`
+	Name         string `json:"name,omitempty"`    //The name of the layout."`
+	Deleted      bool   `json:"deleted,omitempty"` //A flag indicating if the layout is deleted."`
+	Self         string `json:"self,omitempty"`    //A unique reference to the layout resource."`
 }
 
 // VatZone represents a VAT zone.
 type VatZone struct {
-	VatZoneNumber int    `json:"vatZoneNumber"`  // The unique identifier of the VAT-zone.
-	Self          string `json:"self,omitempty"` // A unique link reference to the VAT-zone item.
+	VatZoneNumber      int    `json:"vatZoneNumber"`                // The unique identifier of the VAT-zone.
+	Name               string `json:"name,omitempty"`               // The name of the VAT-zone.
+	EnabledForCustomer bool   `json:"enabledForCustomer,omitempty"` // If this boolean is true, then the VAT-zone can be used for customers.
+	EnabledForSupplier bool   `json:"enabledForSupplier,omitempty"` // If this boolean is true, then the VAT-zone can be used for suppliers.
+	Self               string `json:"self,omitempty"`               // A unique link reference to the VAT-zone item.
+}
+
+// Currency is a currency the agreement can invoice in.
+type Currency struct {
+	Code      string `json:"code"`                // The ISO 4217 3-letter currency code.
+	IsoNumber string `json:"isoNumber,omitempty"` // The ISO 4217 numeric currency code.
+	Name      string `json:"name,omitempty"`      // The name of the currency.
+	Self      string `json:"self,omitempty"`      // A unique reference to the currency resource.
 }
 
 // PaymentTerms represents the default payment terms for the customer.
@@ -49,6 +62,7 @@ type CursorResponse[T any] struct {
 type Pagination struct {
 	FirstPage            string `json:"firstPage"`
 	NextPage             string `json:"nextPage"`
+	PreviousPage         string `json:"previousPage"`
 	LastPage             string `json:"lastPage"`
 	MaxPageSizeAllowed   int    `json:"maxPageSizeAllowed"`
 	PageSize             int    `json:"pageSize"`
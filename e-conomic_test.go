@@ -22,7 +22,7 @@ func TestEconomicCustomer(t *testing.T) {
 			CustomerGroupNumber: 1,
 		},
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	c, err := client.CreateCustomer(c, nil)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
@@ -50,7 +50,7 @@ func TestEconomicCustomer(t *testing.T) {
 }
 
 func TestPaymentTerms(t *testing.T) {
-	client := getTestClient()
+	client := getTestClient(t)
 	terms, err := client.GetPaymentTerms()
 	if err != nil {
 		t.Fatalf("Error: %s", err)
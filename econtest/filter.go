@@ -0,0 +1,286 @@
+package econtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterExpr is a parsed e-conomic filter, e.g.
+// "name$like:abe$and:(balance$gt:10$or:barred$eq:true)".
+type filterExpr struct {
+	// either a group of sub expressions joined by op ...
+	op       string // "$and" or "$or"
+	children []*filterExpr
+	// ... or a single condition
+	field    string
+	operator string
+	raw      string // the value as written, still escaped
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+func parseFilter(s string) (*filterExpr, error) {
+	if s == "" {
+		return nil, nil
+	}
+	p := &filterParser{s: s}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at position %d in filter", p.s[p.pos:], p.pos)
+	}
+	return expr, nil
+}
+
+// $and binds tighter than $or.
+func (p *filterParser) parseOr() (*filterExpr, error) {
+	return p.parseJoined("$or:", p.parseAnd)
+}
+
+func (p *filterParser) parseAnd() (*filterExpr, error) {
+	return p.parseJoined("$and:", p.parseTerm)
+}
+
+func (p *filterParser) parseJoined(sep string, next func() (*filterExpr, error)) (*filterExpr, error) {
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+	children := []*filterExpr{first}
+	for strings.HasPrefix(p.s[p.pos:], sep) {
+		p.pos += len(sep)
+		child, err := next()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &filterExpr{op: strings.TrimSuffix(sep, ":"), children: children}, nil
+}
+
+func (p *filterParser) parseTerm() (*filterExpr, error) {
+	if strings.HasPrefix(p.s[p.pos:], "(") {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(p.s[p.pos:], ")") {
+			return nil, fmt.Errorf("missing ) at position %d in filter", p.pos)
+		}
+		p.pos++
+		return expr, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != '$' {
+		p.pos++
+	}
+	field := p.s[start:p.pos]
+	if field == "" || p.pos == len(p.s) {
+		return nil, fmt.Errorf("expected field and operator at position %d in filter", start)
+	}
+	opEnd := strings.IndexByte(p.s[p.pos:], ':')
+	if opEnd < 0 {
+		return nil, fmt.Errorf("missing : after operator at position %d in filter", p.pos)
+	}
+	operator := p.s[p.pos : p.pos+opEnd]
+	p.pos += opEnd + 1
+	return &filterExpr{field: field, operator: operator, raw: p.readValue()}, nil
+}
+
+const escapable = "$()*,[]"
+
+// readValue reads up to the next unescaped "$and:", "$or:" or ")".
+func (p *filterParser) readValue() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		rest := p.s[p.pos:]
+		if strings.HasPrefix(rest, "$and:") || strings.HasPrefix(rest, "$or:") || rest[0] == ')' {
+			break
+		}
+		if rest[0] == '$' && len(rest) > 1 && strings.IndexByte(escapable, rest[1]) >= 0 {
+			p.pos += 2
+			continue
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func unescape(raw string) string {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '$' && i+1 < len(raw) && strings.IndexByte(escapable, raw[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(raw[i])
+	}
+	return b.String()
+}
+
+// splitList splits the raw value of $in/$nin, "[a,b,c]", on unescaped
+// commas and unescapes each element.
+func splitList(raw string) []string {
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+	if raw == "" {
+		return nil
+	}
+	var values []string
+	start := 0
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '$' && i+1 < len(raw) && strings.IndexByte(escapable, raw[i+1]) >= 0:
+			i++
+		case raw[i] == ',':
+			values = append(values, unescape(raw[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescape(raw[start:]))
+}
+
+func (e *filterExpr) match(item map[string]any) bool {
+	if e == nil {
+		return true
+	}
+	switch e.op {
+	case "$and":
+		for _, c := range e.children {
+			if !c.match(item) {
+				return false
+			}
+		}
+		return true
+	case "$or":
+		for _, c := range e.children {
+			if c.match(item) {
+				return true
+			}
+		}
+		return false
+	}
+	actual, present := lookup(item, e.field)
+	value := unescape(e.raw)
+	if e.raw == "$null" {
+		isNull := !present || actual == nil
+		if e.operator == "$ne" {
+			return !isNull
+		}
+		return isNull
+	}
+	if !present || actual == nil {
+		return e.operator == "$ne" || e.operator == "$nin"
+	}
+	switch e.operator {
+	case "$eq":
+		return compare(actual, value) == 0
+	case "$ne":
+		return compare(actual, value) != 0
+	case "$gt":
+		return compare(actual, value) > 0
+	case "$gte":
+		return compare(actual, value) >= 0
+	case "$lt":
+		return compare(actual, value) < 0
+	case "$lte":
+		return compare(actual, value) <= 0
+	case "$like":
+		return like(fmt.Sprint(actual), value)
+	case "$in", "$nin":
+		found := false
+		for _, v := range splitList(e.raw) {
+			if compare(actual, v) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (e.operator == "$in")
+	}
+	return false
+}
+
+// lookup resolves a dotted path such as "references.other".
+func lookup(item map[string]any, path string) (any, bool) {
+	var current any = item
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// compare compares a stored JSON value with a filter value, numerically for
+// numbers and by calendar date when both sides look like dates.
+func compare(actual any, value string) int {
+	switch a := actual.(type) {
+	case float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return strings.Compare(fmt.Sprint(a), value)
+		}
+		switch {
+		case a < v:
+			return -1
+		case a > v:
+			return 1
+		}
+		return 0
+	case bool:
+		return strings.Compare(strconv.FormatBool(a), strings.ToLower(value))
+	case string:
+		if at, ok := parseDate(a); ok {
+			if vt, ok := parseDate(value); ok {
+				return at.Compare(vt)
+			}
+		}
+		return strings.Compare(a, value)
+	}
+	return strings.Compare(fmt.Sprint(actual), value)
+}
+
+// parseDate parses the calendar date of a date or timestamp, ignoring the
+// time of day so that "2024-09-26" matches "2024-09-26T23:59:59+02:00".
+func parseDate(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02") {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", s[:10])
+	return t, err == nil
+}
+
+func like(actual, pattern string) bool {
+	actual = strings.ToLower(actual)
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	if len(parts) == 1 {
+		return strings.Contains(actual, parts[0])
+	}
+	if !strings.HasPrefix(actual, parts[0]) {
+		return false
+	}
+	actual = actual[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(actual, part)
+		if i < 0 {
+			return false
+		}
+		actual = actual[i+len(part):]
+	}
+	return strings.HasSuffix(actual, last)
+}
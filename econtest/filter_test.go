@@ -0,0 +1,48 @@
+package econtest
+
+import "testing"
+
+func TestFilterMatch(t *testing.T) {
+	item := map[string]any{
+		"name":       "Abe (and) Co, ApS",
+		"balance":    float64(150),
+		"barred":     false,
+		"date":       "2024-09-26",
+		"references": map[string]any{"other": "order-1"},
+	}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"balance$gt:100", true},
+		{"balance$gt:100$and:barred$eq:true", false},
+		{"barred$eq:true$or:balance$lte:150", true},
+		{"references.other$eq:order-1", true},
+		{"name$eq:Abe $(and$) Co$, ApS", true},
+		{"name$like:abe*aps", true},
+		{"name$in:[Bob,Abe $(and$) Co$, ApS]", true},
+		{"balance$nin:[100,150]", false},
+		{"date$gte:2024-09-26T00:00:00+02:00$and:date$lte:2024-09-26T23:59:59+02:00", true},
+		{"balance$lt:10$or:(barred$eq:false$and:(balance$eq:1$or:balance$eq:150))", true},
+		{"ean$eq:$null", true},
+		{"name$ne:$null", true},
+	}
+	for _, tt := range tests {
+		expr, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("%s: %s", tt.filter, err)
+			continue
+		}
+		if got := expr.match(item); got != tt.want {
+			t.Errorf("%s: expected %t, got %t", tt.filter, tt.want, got)
+		}
+	}
+}
+
+func TestFilterParseErrors(t *testing.T) {
+	for _, filter := range []string{"name", "(name$eq:a", "name$eq:a)"} {
+		if _, err := parseFilter(filter); err == nil {
+			t.Errorf("Expected an error parsing %q", filter)
+		}
+	}
+}
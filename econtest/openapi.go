@@ -0,0 +1,197 @@
+package econtest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// serveOpenAPI serves the journals, booked entries and dimensions APIs. The
+// version segment of the path is ignored.
+func (s *Server) serveOpenAPI(res *response, r *http.Request, path string, body []byte) {
+	segs := strings.Split(path, "/")
+	if len(segs) < 3 {
+		res.notFound()
+		return
+	}
+	api, rest := segs[0], segs[2:]
+	switch {
+	case api == "journalsapi" && rest[0] == "draft-entries":
+		s.serveEntries(res, r, "draft-entries", rest[1:], body)
+	case api == "journalsapi" && len(rest) == 3 && rest[0] == "journals" && rest[2] == "book" && r.Method == http.MethodPost:
+		s.bookJournal(res, rest[1])
+	case api == "bookedEntriesapi" && rest[0] == "booked-entries" && r.Method == http.MethodGet:
+		s.serveEntries(res, r, "booked-entries", rest[1:], body)
+	case api == "dimensionsapi" && rest[0] == "values":
+		s.serveDimensionValues(res, r, rest[1:], body)
+	case api == "dimensionsapi" && len(rest) == 2 && rest[0] == "dimension-data" && rest[1] == "draft-entries" && r.Method == http.MethodPost:
+		item, err := decodeObject(body)
+		if err != nil {
+			res.error(http.StatusBadRequest, "E00400", err.Error())
+			return
+		}
+		s.collection("dimension-data").insert(item)
+		res.json(http.StatusCreated, item)
+	default:
+		res.notFound()
+	}
+}
+
+func (s *Server) serveEntries(res *response, r *http.Request, name string, rest []string, body []byte) {
+	c := s.collection(name)
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		s.listCursor(res, r, c.items)
+	case len(rest) == 1 && rest[0] == "count" && r.Method == http.MethodGet:
+		res.json(http.StatusOK, len(c.items))
+	case len(rest) == 0 && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		entry, err := decodeObject(body)
+		if err != nil {
+			res.error(http.StatusBadRequest, "E00400", err.Error())
+			return
+		}
+		if problems := validateEntry(entry); problems != nil {
+			res.errorWithDetails(http.StatusBadRequest, "E04300", "One or more validation errors occurred.", problems)
+			return
+		}
+		if r.Method == http.MethodPost {
+			delete(entry, "entryNumber")
+			c.insert(entry)
+			res.json(http.StatusCreated, entry)
+			return
+		}
+		i, existing := c.find(keyString(entry["entryNumber"]))
+		if existing == nil {
+			res.notFound()
+			return
+		}
+		c.items[i] = entry
+		res.json(http.StatusOK, entry)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		i, existing := c.find(rest[0])
+		if existing == nil {
+			res.notFound()
+			return
+		}
+		c.remove(i)
+		res.json(http.StatusNoContent, nil)
+	default:
+		res.notFound()
+	}
+}
+
+func validateEntry(entry map[string]any) map[string]any {
+	problems := map[string]any{}
+	for _, required := range []string{"journalNumber", "date", "amount"} {
+		if v, ok := entry[required]; !ok || v == nil || v == "" {
+			problems[required] = []string{fmt.Sprintf("The %s field is required.", required)}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// bookJournal moves the draft entries of a journal to the booked entries.
+func (s *Server) bookJournal(res *response, journal string) {
+	drafts := s.collection("draft-entries")
+	booked := s.collection("booked-entries")
+	var remaining []map[string]any
+	for _, entry := range drafts.items {
+		if keyString(entry["journalNumber"]) != journal {
+			remaining = append(remaining, entry)
+			continue
+		}
+		b := clone(entry)
+		delete(b, "entryNumber")
+		booked.insert(b)
+	}
+	drafts.items = remaining
+	res.json(http.StatusOK, nil)
+}
+
+// listCursor writes one page of the filtered items with a cursor to the
+// next page, if any.
+func (s *Server) listCursor(res *response, r *http.Request, items []map[string]any) {
+	q := r.URL.Query()
+	filter, err := parseFilter(q.Get("filter"))
+	if err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	pageSize := s.CursorPageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	start := 0
+	if cursor := q.Get("cursor"); cursor != "" {
+		start, err = strconv.Atoi(cursor)
+		if err != nil {
+			res.error(http.StatusBadRequest, "E00400", "Invalid cursor.")
+			return
+		}
+	}
+	var matches []map[string]any
+	for _, item := range items {
+		if filter.match(item) {
+			matches = append(matches, item)
+		}
+	}
+	start = min(start, len(matches))
+	end := min(start+pageSize, len(matches))
+	page := map[string]any{"items": append([]map[string]any{}, matches[start:end]...)}
+	if end < len(matches) {
+		page["cursor"] = strconv.Itoa(end)
+	}
+	res.json(http.StatusOK, page)
+}
+
+func (s *Server) serveDimensionValues(res *response, r *http.Request, rest []string, body []byte) {
+	switch {
+	case len(rest) == 2 && r.Method == http.MethodGet:
+		c := s.collection("dimensions/" + rest[0] + "/values")
+		if c == nil {
+			res.notFound()
+			return
+		}
+		if _, item := c.find(rest[1]); item != nil {
+			res.json(http.StatusOK, item)
+			return
+		}
+		res.notFound()
+	case len(rest) == 0 && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		value, err := decodeObject(body)
+		if err != nil {
+			res.error(http.StatusBadRequest, "E00400", err.Error())
+			return
+		}
+		c := s.collection("dimensions/" + keyString(value["dimensionNumber"]) + "/values")
+		i, existing := c.find(keyString(value["key"]))
+		if r.Method == http.MethodPost {
+			if existing != nil {
+				res.error(http.StatusConflict, "E06010", "The dimension value already exists.")
+				return
+			}
+			value["objectVersion"] = "1"
+			c.insert(value)
+			res.json(http.StatusCreated, value)
+			return
+		}
+		if existing == nil {
+			res.notFound()
+			return
+		}
+		if value["objectVersion"] != existing["objectVersion"] {
+			res.error(http.StatusConflict, "E00409", "The object version does not match; the value was changed by someone else.")
+			return
+		}
+		version, _ := strconv.Atoi(keyString(existing["objectVersion"]))
+		value["objectVersion"] = strconv.Itoa(version + 1)
+		c.items[i] = value
+		res.json(http.StatusOK, value)
+	default:
+		res.notFound()
+	}
+}
@@ -0,0 +1,482 @@
+package econtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize    = 20
+	defaultMaxPageSize = 1000
+)
+
+// invoiceViews are the read-only invoice collections derived from the booked
+// invoices by their remainder and due date.
+var invoiceViews = map[string]func(invoice map[string]any, today string) bool{
+	"paid": func(i map[string]any, _ string) bool { return number(i["remainder"]) == 0 },
+	"unpaid": func(i map[string]any, _ string) bool {
+		return number(i["remainder"]) != 0
+	},
+	"overdue": func(i map[string]any, today string) bool {
+		due, _ := i["dueDate"].(string)
+		return number(i["remainder"]) != 0 && due != "" && due < today
+	},
+	"not-due": func(i map[string]any, today string) bool {
+		due, _ := i["dueDate"].(string)
+		return number(i["remainder"]) != 0 && (due == "" || due >= today)
+	},
+}
+
+func (s *Server) serveRest(res *response, r *http.Request, path string, body []byte) {
+	segs := strings.Split(path, "/")
+	switch {
+	case path == "self" && r.Method == http.MethodGet:
+		res.json(http.StatusOK, map[string]any{
+			"agreementNumber": 1,
+			"application":     map[string]any{"appNumber": 1, "name": "econtest"},
+			"self":            s.RestURL() + "/self",
+		})
+		return
+	case path == "invoices/booked" && r.Method == http.MethodPost:
+		s.bookInvoice(res, body)
+		return
+	case (path == "orders/sent" || path == "quotes/sent") && r.Method == http.MethodPost:
+		s.send(res, segs[0], body)
+		return
+	case len(segs) == 4 && segs[0] == "invoices" && segs[1] == "drafts" && segs[3] == "lines" && r.Method == http.MethodPost:
+		s.addLines(res, segs[2], body)
+		return
+	case len(segs) > 2 && segs[len(segs)-1] == "pdf" && r.Method == http.MethodGet:
+		s.pdf(res, strings.Join(segs[:len(segs)-2], "/"), segs[len(segs)-2])
+		return
+	case len(segs) == 3 && segs[0] == "product-groups" && segs[2] == "products" && r.Method == http.MethodGet:
+		if _, group := s.collection("product-groups").find(segs[1]); group == nil {
+			res.notFound()
+			return
+		}
+		var items []map[string]any
+		for _, product := range s.collection("products").items {
+			if number, _ := lookup(product, "productGroup.productGroupNumber"); keyString(number) == segs[1] {
+				items = append(items, product)
+			}
+		}
+		s.listPage(res, r, path, items)
+		return
+	case len(segs) == 2 && segs[0] == "invoices" && invoiceViews[segs[1]] != nil && r.Method == http.MethodGet:
+		today := time.Now().Format("2006-01-02")
+		var items []map[string]any
+		for _, invoice := range s.collection("invoices/booked").items {
+			if invoiceViews[segs[1]](invoice, today) {
+				items = append(items, invoice)
+			}
+		}
+		s.listPage(res, r, path, items)
+		return
+	}
+
+	collectionPath, id := path, ""
+	c := s.collection(collectionPath)
+	if c == nil && len(segs) > 1 {
+		collectionPath, id = strings.Join(segs[:len(segs)-1], "/"), segs[len(segs)-1]
+		c = s.collection(collectionPath)
+	}
+	if c == nil || !s.parentsExist(collectionPath) {
+		res.notFound()
+		return
+	}
+
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listPage(res, r, collectionPath, c.items)
+		case http.MethodPost:
+			s.create(res, c, collectionPath, body)
+		default:
+			res.error(http.StatusMethodNotAllowed, "E00405", "Method not allowed.")
+		}
+		return
+	}
+
+	i, item := c.find(id)
+	if item == nil && r.Method == http.MethodPut && c.upsert {
+		s.upsert(res, c, collectionPath, id, body)
+		return
+	}
+	if item == nil {
+		res.notFound()
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		res.json(http.StatusOK, item)
+	case http.MethodPut:
+		updated, err := decodeObject(body)
+		if err != nil {
+			res.error(http.StatusBadRequest, "E00400", err.Error())
+			return
+		}
+		if !strings.Contains(c.key, ".") {
+			updated[c.key] = item[c.key]
+		}
+		s.decorate(collectionPath, updated)
+		c.items[i] = updated
+		res.json(http.StatusOK, updated)
+	case http.MethodPatch:
+		if err := applyPatch(item, body); err != nil {
+			res.error(http.StatusBadRequest, "E00400", err.Error())
+			return
+		}
+		res.json(http.StatusOK, item)
+	case http.MethodDelete:
+		c.remove(i)
+		res.json(http.StatusNoContent, nil)
+	default:
+		res.error(http.StatusMethodNotAllowed, "E00405", "Method not allowed.")
+	}
+}
+
+// parentsExist checks that every numbered parent of a nested collection,
+// e.g. the customer of "customers/5/contacts", exists.
+func (s *Server) parentsExist(path string) bool {
+	segs := strings.Split(path, "/")
+	for i := 1; i < len(segs); i++ {
+		parent := s.collection(strings.Join(segs[:i], "/"))
+		if parent == nil {
+			continue
+		}
+		if _, item := parent.find(segs[i]); item == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// upsert creates the entity a PUT addressed as id.
+func (s *Server) upsert(res *response, c *collection, path, id string, body []byte) {
+	item, err := decodeObject(body)
+	if err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	if c.id(item) != id {
+		res.error(http.StatusBadRequest, "E00400", fmt.Sprintf("The %s in the body does not match the URL.", c.key))
+		return
+	}
+	c.insert(item)
+	s.decorate(path, item)
+	res.json(http.StatusCreated, item)
+}
+
+func (s *Server) create(res *response, c *collection, path string, body []byte) {
+	item, err := decodeObject(body)
+	if err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	if !c.insert(item) {
+		res.errorWithDetails(http.StatusBadRequest, "E04300", "Validation failed. 1 error found.",
+			propertyError(c.key, "E06010", "The number is already in use.", item[c.key]))
+		return
+	}
+	s.decorate(path, item)
+	res.json(http.StatusCreated, item)
+}
+
+// decorate fills in the read-only properties e-conomic computes itself.
+func (s *Server) decorate(path string, item map[string]any) {
+	item["self"] = fmt.Sprintf("%s/%s/%s", s.RestURL(), path, s.collection(path).id(item))
+	segs := strings.Split(path, "/")
+	switch {
+	case len(segs) == 3 && segs[0] == "customers" && segs[2] == "contacts":
+		customerNumber, _ := strconv.ParseFloat(segs[1], 64)
+		item["customer"] = map[string]any{"customerNumber": customerNumber}
+	case path == "invoices/drafts" || path == "invoices/booked":
+		computeTotals(item)
+		item["pdf"] = map[string]any{"download": item["self"].(string) + "/pdf"}
+	case segs[0] == "orders" || segs[0] == "quotes":
+		computeTotals(item)
+		item["pdf"] = map[string]any{"download": item["self"].(string) + "/pdf"}
+	}
+}
+
+// computeTotals sums the lines of an invoice with a flat 25% VAT.
+func computeTotals(invoice map[string]any) {
+	lines, _ := invoice["lines"].([]any)
+	net := 0.0
+	for _, l := range lines {
+		line, _ := l.(map[string]any)
+		net += number(line["quantity"]) * number(line["unitNetPrice"]) * (1 - number(line["discountPercentage"])/100)
+	}
+	net = math.Round(net*100) / 100
+	vat := math.Round(net*25) / 100
+	invoice["netAmount"] = net
+	invoice["netAmountInBaseCurrency"] = net
+	invoice["vatAmount"] = vat
+	invoice["grossAmount"] = net + vat
+	invoice["grossAmountInBaseCurrency"] = net + vat
+}
+
+func (s *Server) bookInvoice(res *response, body []byte) {
+	var req struct {
+		DraftInvoice struct {
+			DraftInvoiceNumber json.Number `json:"draftInvoiceNumber"`
+		} `json:"draftInvoice"`
+		BookWithNumber json.Number `json:"bookWithNumber"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	drafts := s.collection("invoices/drafts")
+	i, draft := drafts.find(req.DraftInvoice.DraftInvoiceNumber.String())
+	if draft == nil {
+		res.errorWithDetails(http.StatusBadRequest, "E04300", "Validation failed. 1 error found.",
+			propertyError("draftInvoiceNumber", "E07100", "Draft invoice not found.", req.DraftInvoice.DraftInvoiceNumber))
+		return
+	}
+	booked := clone(draft)
+	delete(booked, "draftInvoiceNumber")
+	if req.BookWithNumber != "" {
+		n, _ := req.BookWithNumber.Float64()
+		booked["bookedInvoiceNumber"] = n
+	}
+	computeTotals(booked)
+	booked["remainder"] = booked["grossAmount"]
+	booked["remainderInBaseCurrency"] = booked["grossAmountInBaseCurrency"]
+	bookedInvoices := s.collection("invoices/booked")
+	if !bookedInvoices.insert(booked) {
+		res.errorWithDetails(http.StatusBadRequest, "E04300", "Validation failed. 1 error found.",
+			propertyError("bookWithNumber", "E06010", "The number is already in use.", req.BookWithNumber))
+		return
+	}
+	s.decorate("invoices/booked", booked)
+	drafts.remove(i)
+	res.json(http.StatusCreated, booked)
+}
+
+// addLines appends the lines of body to the draft invoice id, as POST
+// /invoices/drafts/:id/lines does. Lines without a lineNumber are numbered
+// after the existing ones.
+func (s *Server) addLines(res *response, id string, body []byte) {
+	var req struct {
+		Lines []map[string]any `json:"lines"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	_, draft := s.collection("invoices/drafts").find(id)
+	if draft == nil {
+		res.notFound()
+		return
+	}
+	lines, _ := draft["lines"].([]any)
+	next := 0.0
+	for _, l := range lines {
+		line, _ := l.(map[string]any)
+		next = math.Max(next, number(line["lineNumber"]))
+	}
+	added := []any{}
+	for _, line := range req.Lines {
+		if number(line["lineNumber"]) == 0 {
+			next++
+			line["lineNumber"] = next
+		}
+		added = append(added, line)
+	}
+	draft["lines"] = append(lines, added...)
+	computeTotals(draft)
+	res.json(http.StatusCreated, map[string]any{"lines": added})
+}
+
+// send moves a draft order or quote to the sent ones, as POST /orders/sent
+// and /quotes/sent do with the draft as the body. kind is "orders" or
+// "quotes".
+func (s *Server) send(res *response, kind string, body []byte) {
+	item, err := decodeObject(body)
+	if err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	drafts := s.collection(kind + "/drafts")
+	i, draft := drafts.find(drafts.id(item))
+	if draft == nil {
+		res.errorWithDetails(http.StatusBadRequest, "E04300", "Validation failed. 1 error found.",
+			propertyError(drafts.key, "E07100", "Draft not found.", item[drafts.key]))
+		return
+	}
+	sent := clone(draft)
+	s.collection(kind + "/sent").insert(sent)
+	s.decorate(kind+"/sent", sent)
+	drafts.remove(i)
+	res.json(http.StatusCreated, sent)
+}
+
+// pdf writes a placeholder PDF for the entity id in the collection at path.
+func (s *Server) pdf(res *response, path, id string) {
+	c := s.collection(path)
+	if c == nil {
+		res.notFound()
+		return
+	}
+	if _, item := c.find(id); item == nil {
+		res.notFound()
+		return
+	}
+	res.w.Header().Set("Content-Type", "application/pdf")
+	res.w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(res.w, "%%PDF-1.4\n%% econtest %s/%s\n%%%%EOF\n", path, id)
+}
+
+// listPage writes one page of the filtered items as a REST collection.
+func (s *Server) listPage(res *response, r *http.Request, path string, items []map[string]any) {
+	q := r.URL.Query()
+	filter, err := parseFilter(q.Get("filter"))
+	if err != nil {
+		res.error(http.StatusBadRequest, "E00400", err.Error())
+		return
+	}
+	maxPageSize := s.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = defaultMaxPageSize
+	}
+	pageSize := min(defaultPageSize, maxPageSize)
+	if v := q.Get("pagesize"); v != "" {
+		pageSize, err = strconv.Atoi(v)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			res.error(http.StatusBadRequest, "E00400", fmt.Sprintf("pagesize must be between 1 and %d.", maxPageSize))
+			return
+		}
+	}
+	skipPages, _ := strconv.Atoi(q.Get("skippages"))
+
+	var matches []map[string]any
+	for _, item := range items {
+		if filter.match(item) {
+			matches = append(matches, item)
+		}
+	}
+	if sortBy := q.Get("sort"); sortBy != "" {
+		sortItems(matches, strings.Split(sortBy, ","))
+	}
+	start := min(skipPages*pageSize, len(matches))
+	end := min(start+pageSize, len(matches))
+	// Like e-conomic, collections leave out the lines of documents; they
+	// are only returned when a single document is fetched.
+	page := make([]map[string]any, 0, end-start)
+	for _, item := range matches[start:end] {
+		if _, ok := item["lines"]; ok {
+			item = clone(item)
+			delete(item, "lines")
+		}
+		page = append(page, item)
+	}
+
+	link := func(skip int) string {
+		p := url.Values{}
+		for k, v := range q {
+			p[k] = v
+		}
+		p.Set("skippages", strconv.Itoa(skip))
+		p.Set("pagesize", strconv.Itoa(pageSize))
+		return fmt.Sprintf("%s/%s?%s", s.RestURL(), path, p.Encode())
+	}
+	lastPage := 0
+	if len(matches) > 0 {
+		lastPage = (len(matches) - 1) / pageSize
+	}
+	pagination := map[string]any{
+		"maxPageSizeAllowed":   maxPageSize,
+		"skipPages":            skipPages,
+		"pageSize":             pageSize,
+		"results":              len(matches),
+		"resultsWithoutFilter": len(items),
+		"firstPage":            link(0),
+		"lastPage":             link(lastPage),
+	}
+	if skipPages < lastPage {
+		pagination["nextPage"] = link(skipPages + 1)
+	}
+	if skipPages > 0 {
+		pagination["previousPage"] = link(skipPages - 1)
+	}
+	res.json(http.StatusOK, map[string]any{
+		"collection": page,
+		"pagination": pagination,
+		"self":       link(skipPages),
+	})
+}
+
+// sortItems orders items by the given properties; a "-" prefix sorts in
+// descending order. Missing values sort first.
+func sortItems(items []map[string]any, properties []string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, property := range properties {
+			desc := strings.HasPrefix(property, "-")
+			property = strings.TrimPrefix(property, "-")
+			a, _ := lookup(items[i], property)
+			b, _ := lookup(items[j], property)
+			c := 0
+			switch {
+			case a == nil && b == nil:
+			case a == nil:
+				c = -1
+			case b == nil:
+				c = 1
+			default:
+				c = compare(a, keyString(b))
+			}
+			if c != 0 {
+				return (c < 0) != desc
+			}
+		}
+		return false
+	})
+}
+
+// applyPatch applies the replace/add operations of a JSON patch document.
+func applyPatch(item map[string]any, body []byte) error {
+	var ops []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if op.Op != "replace" && op.Op != "add" {
+			return fmt.Errorf("unsupported patch operation %q", op.Op)
+		}
+		parts := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+		target := item
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := target[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				target[part] = next
+			}
+			target = next
+		}
+		target[parts[len(parts)-1]] = op.Value
+	}
+	return nil
+}
+
+func decodeObject(body []byte) (map[string]any, error) {
+	var item map[string]any
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("request body is not a JSON object: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("request body is empty")
+	}
+	return item, nil
+}
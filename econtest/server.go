@@ -0,0 +1,460 @@
+// Package econtest provides an in-memory stand-in for the parts of the
+// e-conomic REST API and OpenAPI endpoints that this library uses, so code
+// built on it can be tested offline.
+//
+//	srv := econtest.NewServer()
+//	defer srv.Close()
+//	client := &economic.Client{
+//		AgreementGrant: econtest.AgreementGrant,
+//		AppSecretToken: econtest.AppSecretToken,
+//		RestBaseURL:    srv.RestURL(),
+//		OpenAPIBaseURL: srv.OpenAPIURL(),
+//	}
+//
+// Entities are kept as decoded JSON objects, so any value that marshals to
+// the e-conomic shape (such as the economic package's own structs) can be
+// seeded with Add.
+package econtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The tokens the server accepts; any other value gets a 401.
+const (
+	AgreementGrant = "econtest-agreement-grant"
+	AppSecretToken = "econtest-app-secret-token"
+)
+
+const (
+	restPrefix    = "/rest"
+	openAPIPrefix = "/api"
+)
+
+// Server is an in-memory e-conomic. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// CursorPageSize is the number of items per page on the cursor based
+	// OpenAPI endpoints. Defaults to 100.
+	CursorPageSize int
+
+	// MaxPageSize is the largest pagesize the REST collections accept, as
+	// reported in their maxPageSizeAllowed. Defaults to 1000.
+	MaxPageSize int
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	requests    []Request
+	failures    []failure
+	logIds      int
+}
+
+// Request is a request received by the server, as returned by Requests.
+type Request struct {
+	Method string
+	Path   string // without the /rest or /api prefix
+	Query  string
+	Body   []byte
+}
+
+type failure struct {
+	method string
+	path   string
+	status int
+	after  bool // process the request before failing
+}
+
+// collection holds the entities of one endpoint in insertion order.
+type collection struct {
+	key    string // path of the identifying property, e.g. "customerNumber" or "currency.code"
+	items  []map[string]any
+	next   int  // last number handed out
+	upsert bool // PUT creates missing entities
+}
+
+// NewServer starts a server with an empty agreement.
+func NewServer() *Server {
+	s := &Server{collections: map[string]*collection{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// RestURL is the base URL to use for economic.Client.RestBaseURL.
+func (s *Server) RestURL() string {
+	return s.URL + restPrefix
+}
+
+// OpenAPIURL is the base URL to use for economic.Client.OpenAPIBaseURL.
+func (s *Server) OpenAPIURL() string {
+	return s.URL + openAPIPrefix
+}
+
+// Add stores entities in the collection at path, e.g. "customers",
+// "customers/1/contacts", "payment-terms" or "invoices/booked". Entities
+// without a number get the next free one. It panics if an entity cannot be
+// marshalled to a JSON object or the path is unknown.
+func (s *Server) Add(path string, entities ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(strings.Trim(path, "/"))
+	if c == nil {
+		panic(fmt.Sprintf("econtest: unknown collection %q", path))
+	}
+	for _, e := range entities {
+		item, err := toObject(e)
+		if err != nil {
+			panic(fmt.Sprintf("econtest: %s", err))
+		}
+		c.insert(item)
+	}
+}
+
+// Items returns a copy of the entities in the collection at path.
+func (s *Server) Items(path string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(strings.Trim(path, "/"))
+	if c == nil {
+		return nil
+	}
+	items := make([]map[string]any, len(c.items))
+	for i, item := range c.items {
+		items[i] = clone(item)
+	}
+	return items
+}
+
+// Get returns a copy of the entity identified by id in the collection at
+// path, or nil.
+func (s *Server) Get(path string, id any) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(strings.Trim(path, "/"))
+	if c == nil {
+		return nil
+	}
+	if _, item := c.find(fmt.Sprint(id)); item != nil {
+		return clone(item)
+	}
+	return nil
+}
+
+// Update applies fn to the stored entity identified by id, e.g. to mark a
+// booked invoice as paid by setting "remainder" to 0. It reports whether the
+// entity was found.
+func (s *Server) Update(path string, id any, fn func(item map[string]any)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(strings.Trim(path, "/"))
+	if c == nil {
+		return false
+	}
+	_, item := c.find(fmt.Sprint(id))
+	if item == nil {
+		return false
+	}
+	fn(item)
+	return true
+}
+
+// FailNext makes the next request with the given method whose path starts
+// with path (without the /rest or /api prefix, e.g. "invoices/booked" or
+// "/journalsapi") fail with status. Failures are consumed in order.
+func (s *Server) FailNext(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, path: strings.Trim(path, "/"), status: status})
+}
+
+// LoseNextResponse is FailNext for a request that e-conomic processes but
+// whose response is lost, as when a write times out at the gateway. The
+// request takes effect and the client gets status instead of the result.
+func (s *Server) LoseNextResponse(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, path: strings.Trim(path, "/"), status: status, after: true})
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	var path string
+	rest := strings.HasPrefix(r.URL.Path, restPrefix+"/")
+	switch {
+	case rest:
+		path = strings.Trim(strings.TrimPrefix(r.URL.Path, restPrefix), "/")
+	case strings.HasPrefix(r.URL.Path, openAPIPrefix+"/"):
+		path = strings.Trim(strings.TrimPrefix(r.URL.Path, openAPIPrefix), "/")
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.RawQuery, Body: body})
+	res := &response{w: w, rest: rest, server: s}
+
+	if r.Header.Get("X-AgreementGrantToken") != AgreementGrant || r.Header.Get("X-AppSecretToken") != AppSecretToken {
+		res.error(http.StatusUnauthorized, "E00001", "Invalid agreement grant or app secret token.")
+		return
+	}
+	for i, f := range s.failures {
+		if f.method == r.Method && strings.HasPrefix(path, f.path) {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			if f.after {
+				s.serve(&response{w: httptest.NewRecorder(), rest: rest, server: s}, r, path, body)
+			}
+			res.error(f.status, "E00500", fmt.Sprintf("Injected failure (%d).", f.status))
+			return
+		}
+	}
+	s.serve(res, r, path, body)
+}
+
+func (s *Server) serve(res *response, r *http.Request, path string, body []byte) {
+	if res.rest {
+		s.serveRest(res, r, path, body)
+	} else {
+		s.serveOpenAPI(res, r, path, body)
+	}
+}
+
+// response writes JSON and error bodies in the format of either API.
+type response struct {
+	w      http.ResponseWriter
+	rest   bool
+	server *Server
+}
+
+func (res *response) json(status int, v any) {
+	res.w.Header().Set("Content-Type", "application/json")
+	res.w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(res.w).Encode(v)
+	}
+}
+
+func (res *response) error(status int, code, message string) {
+	res.errorWithDetails(status, code, message, nil)
+}
+
+func (res *response) errorWithDetails(status int, code, message string, details map[string]any) {
+	res.server.logIds++
+	logId := fmt.Sprintf("econtest-%d", res.server.logIds)
+	if res.rest {
+		body := map[string]any{
+			"message":        message,
+			"errorCode":      code,
+			"developerHint":  "This error was produced by the econtest server.",
+			"logId":          logId,
+			"httpStatusCode": status,
+		}
+		if details != nil {
+			body["errors"] = details
+		}
+		res.json(status, body)
+		return
+	}
+	body := map[string]any{
+		"type":    "https://tools.ietf.org/html/rfc7231",
+		"title":   http.StatusText(status),
+		"status":  status,
+		"detail":  message,
+		"traceId": logId,
+	}
+	if details != nil {
+		body["errors"] = details
+	}
+	res.w.Header().Set("Content-Type", "application/problem+json")
+	res.w.WriteHeader(status)
+	json.NewEncoder(res.w).Encode(body)
+}
+
+func (res *response) notFound() {
+	res.error(http.StatusNotFound, "E07000", "The requested resource was not found.")
+}
+
+// propertyError builds the nested REST validation error for one property.
+func propertyError(property, code, message string, input any) map[string]any {
+	return map[string]any{property: map[string]any{"errors": []any{map[string]any{
+		"propertyName": property,
+		"errorCode":    code,
+		"errorMessage": message,
+		"inputValue":   input,
+	}}}}
+}
+
+// collectionKeys lists the collections the server knows, by path pattern,
+// with the property that identifies their entities. The REST collections use
+// their REST path; the OpenAPI ones are named after their last path segment.
+var collectionKeys = []struct{ pattern, key string }{
+	{"customers", "customerNumber"},
+	{"customers/*/contacts", "customerContactNumber"},
+	{"payment-terms", "paymentTermsNumber"},
+	{"layouts", "layoutNumber"},
+	{"vat-zones", "vatZoneNumber"},
+	{"units", "unitNumber"},
+	{"currencies", "code"},
+	{"customer-groups", "customerGroupNumber"},
+	{"employees", "employeeNumber"},
+	{"employee-groups", "employeeGroupNumber"},
+	{"products", "productNumber"},
+	{"product-groups", "productGroupNumber"},
+	{"products/*/pricing/currency-specific-sales-prices", "currency.code"},
+	{"orders/drafts", "orderNumber"},
+	{"orders/sent", "orderNumber"},
+	{"orders/archived", "orderNumber"},
+	{"quotes/drafts", "quoteNumber"},
+	{"quotes/sent", "quoteNumber"},
+	{"quotes/archived", "quoteNumber"},
+	{"invoices/drafts", "draftInvoiceNumber"},
+	{"invoices/booked", "bookedInvoiceNumber"},
+	{"draft-entries", "entryNumber"},
+	{"booked-entries", "entryNumber"},
+	{"dimensions/*/values", "key"},
+	{"dimension-data", "id"},
+	{"accounts", "accountNumber"},
+	{"accounts/*/accounting-years/*/entries", "entryNumber"},
+	{"accounting-years", "year"},
+	{"accounting-years/*/periods", "periodNumber"},
+}
+
+// upsertCollections are the collections, by pattern, where PUT creates
+// entities that do not exist yet.
+var upsertCollections = map[string]bool{
+	"products/*/pricing/currency-specific-sales-prices": true,
+}
+
+func collectionKey(path string) (key, pattern string, ok bool) {
+	segs := strings.Split(path, "/")
+	for _, c := range collectionKeys {
+		if matchSegments(strings.Split(c.pattern, "/"), segs) {
+			return c.key, c.pattern, true
+		}
+	}
+	return "", "", false
+}
+
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) != len(segs) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != segs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// collection returns the collection at path, creating it on first use, or
+// nil if path is not a known collection.
+func (s *Server) collection(path string) *collection {
+	if c, ok := s.collections[path]; ok {
+		return c
+	}
+	key, pattern, ok := collectionKey(path)
+	if !ok {
+		return nil
+	}
+	c := &collection{key: key, upsert: upsertCollections[pattern]}
+	s.collections[path] = c
+	return c
+}
+
+// id returns the identifying property of item as a string.
+func (c *collection) id(item map[string]any) string {
+	v, _ := lookup(item, c.key)
+	return keyString(v)
+}
+
+func (c *collection) find(id string) (int, map[string]any) {
+	for i, item := range c.items {
+		if c.id(item) == id {
+			return i, item
+		}
+	}
+	return -1, nil
+}
+
+// insert stores item, handing out the next number if it has none. It returns
+// false if an item with the same number exists.
+func (c *collection) insert(item map[string]any) bool {
+	id := c.id(item)
+	if (id == "" || id == "0") && !strings.Contains(c.key, ".") {
+		c.next++
+		for {
+			if _, existing := c.find(strconv.Itoa(c.next)); existing == nil {
+				break
+			}
+			c.next++
+		}
+		item[c.key] = float64(c.next)
+		id = strconv.Itoa(c.next)
+	} else if _, existing := c.find(id); existing != nil {
+		return false
+	}
+	if n, err := strconv.Atoi(id); err == nil && n > c.next {
+		c.next = n
+	}
+	c.items = append(c.items, item)
+	return true
+}
+
+func (c *collection) remove(i int) {
+	c.items = append(c.items[:i], c.items[i+1:]...)
+}
+
+func keyString(v any) string {
+	switch k := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(k, 'f', -1, 64)
+	case string:
+		return k
+	}
+	return fmt.Sprint(v)
+}
+
+func toObject(v any) (map[string]any, error) {
+	if m, ok := v.(map[string]any); ok {
+		return clone(m), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%T is not a JSON object: %w", v, err)
+	}
+	return m, nil
+}
+
+func clone(item map[string]any) map[string]any {
+	b, _ := json.Marshal(item)
+	var m map[string]any
+	json.Unmarshal(b, &m)
+	return m
+}
+
+func number(v any) float64 {
+	f, _ := v.(float64)
+	return f
+}
@@ -0,0 +1,85 @@
+package econtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, srv *Server, url string, v any) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-AgreementGrantToken", AgreementGrant)
+	req.Header.Set("X-AppSecretToken", AppSecretToken)
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	defer res.Body.Close()
+	if v != nil {
+		json.NewDecoder(res.Body).Decode(v)
+	}
+	return res.StatusCode
+}
+
+func TestPagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := 0; i < 5; i++ {
+		srv.Add("customers", map[string]any{"name": "c", "currency": "DKK"})
+	}
+	srv.Add("customers", map[string]any{"name": "other", "currency": "EUR"})
+
+	var page struct {
+		Collection []map[string]any `json:"collection"`
+		Pagination struct {
+			Results  int    `json:"results"`
+			NextPage string `json:"nextPage"`
+		} `json:"pagination"`
+	}
+	url := srv.RestURL() + "/customers?filter=currency$eq:DKK&pagesize=2"
+	var numbers []float64
+	for pages := 0; url != ""; pages++ {
+		if pages > 3 {
+			t.Fatalf("Expected 3 pages")
+		}
+		page.Pagination.NextPage = ""
+		if status := get(t, srv, url, &page); status != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", status)
+		}
+		for _, c := range page.Collection {
+			numbers = append(numbers, c["customerNumber"].(float64))
+		}
+		if next := page.Pagination.NextPage; next != "" && !strings.Contains(next, "filter=currency") {
+			t.Fatalf("Expected the filter to be kept in %s", next)
+		}
+		url = page.Pagination.NextPage
+	}
+	if len(numbers) != 5 || page.Pagination.Results != 5 {
+		t.Fatalf("Expected 5 customers, got %v", numbers)
+	}
+}
+
+func TestAuthAndFailures(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	res, err := srv.Client().Get(srv.RestURL() + "/customers")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 without tokens, got %d", res.StatusCode)
+	}
+	srv.FailNext(http.MethodGet, "customers", http.StatusServiceUnavailable)
+	if status := get(t, srv, srv.RestURL()+"/customers", nil); status != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the injected 503, got %d", status)
+	}
+	if status := get(t, srv, srv.RestURL()+"/customers", nil); status != http.StatusOK {
+		t.Fatalf("Expected 200 after the injected failure, got %d", status)
+	}
+	if status := get(t, srv, srv.RestURL()+"/customers/1/contacts", nil); status != http.StatusNotFound {
+		t.Fatalf("Expected 404 for contacts of a missing customer, got %d", status)
+	}
+}
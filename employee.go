@@ -0,0 +1,180 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var ErrEmployeeNotFound = errors.New("employee not found")
+
+// Employee is a person working for the agreement, who can be the sales
+// person or vendor reference on customers, orders and invoices.
+type Employee struct {
+	EmployeeNumber int            `json:"employeeNumber,omitempty"` // The unique identifier of the employee. Assigned by e-conomic when not set.
+	Name           string         `json:"name"`                     // The employee's name.
+	Email          string         `json:"email,omitempty"`          // The employee's email address.
+	Phone          string         `json:"phone,omitempty"`          // The employee's phone number.
+	Barred         bool           `json:"barred,omitempty"`         // Barred employees cannot be used on new documents.
+	EmployeeGroup  *EmployeeGroup `json:"employeeGroup,omitempty"`  // The group the employee belongs to. Required when creating an employee.
+	Self           string         `json:"self,omitempty"`           // A unique link reference to the employee item.
+}
+
+// EmployeeGroup represents an employee group.
+type EmployeeGroup struct {
+	EmployeeGroupNumber int    `json:"employeeGroupNumber"` // The unique identifier of the employee group.
+	Name                string `json:"name,omitempty"`      // The name of the employee group.
+	Self                string `json:"self,omitempty"`      // A unique link reference to the employee group item.
+}
+
+// SalesPerson references the employee, for Customer.SalesPerson and
+// References.SalesPerson.
+func (e *Employee) SalesPerson() *SalesPerson {
+	return &SalesPerson{EmployeeNumber: e.EmployeeNumber}
+}
+
+// VendorReference references the employee, for References.VendorReference.
+func (e *Employee) VendorReference() *VendorReference {
+	return &VendorReference{EmployeeNumber: e.EmployeeNumber}
+}
+
+func (client *Client) GetEmployees() ([]Employee, error) {
+	return client.GetEmployeesContext(context.Background())
+}
+
+func (client *Client) GetEmployeesContext(ctx context.Context) ([]Employee, error) {
+	tc := &TypedClient[Employee]{client: client}
+	return tc.getEntities(ctx, "employees", ListOptions{})
+}
+
+func (client *Client) GetEmployee(employeeNumber int) (*Employee, error) {
+	return client.GetEmployeeContext(context.Background(), employeeNumber)
+}
+
+func (client *Client) GetEmployeeContext(ctx context.Context, employeeNumber int) (*Employee, error) {
+	var employee Employee
+	err := client.callRestAPI(ctx, fmt.Sprintf("employees/%d", employeeNumber), http.MethodGet, nil, &employee)
+	return &employee, err
+}
+
+func (client *Client) CreateEmployee(employee *Employee) (*Employee, error) {
+	return client.CreateEmployeeContext(context.Background(), employee)
+}
+
+// CreateEmployeeContext creates an employee. Name and EmployeeGroup are
+// required.
+func (client *Client) CreateEmployeeContext(ctx context.Context, employee *Employee) (*Employee, error) {
+	if employee == nil || employee.Name == "" || employee.EmployeeGroup == nil {
+		return nil, fmt.Errorf("an employee needs a name and an employee group")
+	}
+	var created Employee
+	var lookup []lookupFunc
+	if employee.EmployeeNumber != 0 {
+		lookup = append(lookup, client.employeeLookup(employee.EmployeeNumber, &created))
+	}
+	err := client.callRestAPI(ctx, "employees", http.MethodPost, employee, &created, lookup...)
+	return &created, err
+}
+
+// employeeLookup finds an employee created by a failed CreateEmployee. It is
+// only possible when the caller chose the employee number.
+func (client *Client) employeeLookup(employeeNumber int, employee *Employee) lookupFunc {
+	return func(ctx context.Context) (bool, error) {
+		e, err := client.GetEmployeeContext(ctx, employeeNumber)
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		*employee = *e
+		return true, nil
+	}
+}
+
+func (client *Client) UpdateEmployee(employee *Employee) (*Employee, error) {
+	return client.UpdateEmployeeContext(context.Background(), employee)
+}
+
+// UpdateEmployeeContext replaces the employee with the same EmployeeNumber.
+func (client *Client) UpdateEmployeeContext(ctx context.Context, employee *Employee) (*Employee, error) {
+	if employee == nil || employee.EmployeeNumber == 0 {
+		return nil, fmt.Errorf("cannot update an employee without an employee number")
+	}
+	var updated Employee
+	err := client.callRestAPI(ctx, fmt.Sprintf("employees/%d", employee.EmployeeNumber), http.MethodPut, employee, &updated)
+	return &updated, err
+}
+
+func (client *Client) GetEmployeeGroups() ([]EmployeeGroup, error) {
+	return client.GetEmployeeGroupsContext(context.Background())
+}
+
+func (client *Client) GetEmployeeGroupsContext(ctx context.Context) ([]EmployeeGroup, error) {
+	tc := &TypedClient[EmployeeGroup]{client: client}
+	return tc.getEntities(ctx, "employee-groups", ListOptions{})
+}
+
+func (client *Client) FindEmployeeByEmail(email string) (*Employee, error) {
+	return client.FindEmployeeByEmailContext(context.Background(), email)
+}
+
+// FindEmployeeByEmailContext finds the employee with the email address,
+// ignoring case and surrounding spaces. Barred employees are skipped unless
+// no other employee matches. It returns ErrEmployeeNotFound if none does.
+func (client *Client) FindEmployeeByEmailContext(ctx context.Context, email string) (*Employee, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, ErrEmployeeNotFound
+	}
+	matches, err := client.findEmployees(ctx, func(e Employee) bool {
+		return strings.EqualFold(strings.TrimSpace(e.Email), email)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: no employee with email %q", ErrEmployeeNotFound, email)
+	}
+	for _, e := range matches {
+		if !e.Barred {
+			return &e, nil
+		}
+	}
+	return &matches[0], nil
+}
+
+func (client *Client) FindEmployeesByName(name string) ([]Employee, error) {
+	return client.FindEmployeesByNameContext(context.Background(), name)
+}
+
+// FindEmployeesByNameContext returns the employees with the name, ignoring
+// case and surrounding spaces. Names are not unique, so there can be several.
+func (client *Client) FindEmployeesByNameContext(ctx context.Context, name string) ([]Employee, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	return client.findEmployees(ctx, func(e Employee) bool {
+		return strings.EqualFold(strings.TrimSpace(e.Name), name)
+	})
+}
+
+// findEmployees matches locally rather than with a filter, so that addresses
+// typed differently in another user directory still match. Agreements have
+// few employees.
+func (client *Client) findEmployees(ctx context.Context, match func(Employee) bool) ([]Employee, error) {
+	employees, err := client.GetEmployeesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Employee
+	for _, e := range employees {
+		if match(e) {
+			matches = append(matches, e)
+		}
+	}
+	return matches, nil
+}
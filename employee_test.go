@@ -0,0 +1,56 @@
+package economic
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmployees(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("employee-groups", EmployeeGroup{EmployeeGroupNumber: 1, Name: "Salg"})
+	srv.Add("employees", Employee{EmployeeNumber: 1, Name: "Old Sales", Email: "sales@example.com", Barred: true, EmployeeGroup: &EmployeeGroup{EmployeeGroupNumber: 1}})
+
+	created, err := client.CreateEmployee(&Employee{Name: "Jane Seller", Email: "Sales@Example.com", EmployeeGroup: &EmployeeGroup{EmployeeGroupNumber: 1}})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if created.EmployeeNumber == 0 {
+		t.Fatalf("Expected an employee number, got %+v", created)
+	}
+	if _, err := client.CreateEmployee(&Employee{Name: "No Group"}); err == nil {
+		t.Fatalf("Expected an error creating an employee without a group")
+	}
+
+	found, err := client.FindEmployeeByEmail(" sales@EXAMPLE.com ")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if found.EmployeeNumber != created.EmployeeNumber {
+		t.Fatalf("Expected the employee who is not barred, got %+v", found)
+	}
+	if _, err := client.FindEmployeeByEmail("nobody@example.com"); !errors.Is(err, ErrEmployeeNotFound) {
+		t.Fatalf("Expected ErrEmployeeNotFound, got %v", err)
+	}
+	byName, err := client.FindEmployeesByName("jane seller")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(byName) != 1 {
+		t.Fatalf("Expected one employee, got %+v", byName)
+	}
+
+	found.Phone = "12345678"
+	updated, err := client.UpdateEmployee(found)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if updated.Phone != "12345678" {
+		t.Fatalf("Expected the phone number to be updated, got %+v", updated)
+	}
+
+	order := testOrder("")
+	order.References = &References{SalesPerson: found.SalesPerson(), VendorReference: found.VendorReference()}
+	if order.References.SalesPerson.EmployeeNumber != created.EmployeeNumber {
+		t.Fatalf("Expected the sales person to reference the employee, got %+v", order.References.SalesPerson)
+	}
+}
@@ -0,0 +1,264 @@
+package economic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Error codes returned by e-conomic that callers commonly branch on.
+const (
+	ErrorCodeAlreadyExists = "E06010" // e.g. a customer number that is already in use
+)
+
+// APIError is returned for any non-2xx response from either the REST API or
+// the OpenAPI endpoints. The e-conomic error body is parsed into the typed
+// fields when possible; Body always holds the raw response.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response.
+	Method     string // HTTP method of the failed request.
+	Endpoint   string // Endpoint (without base URL) of the failed request.
+
+	ErrorCode     string            // e-conomic error code, e.g. "E04300".
+	Message       string            // Human readable message.
+	DeveloperHint string            // Hint on how to fix the request.
+	LogId         string            // Id to give e-conomic support (traceId on the OpenAPI endpoints).
+	Errors        []ValidationError // Per-property validation errors, if any.
+
+	Body string // The raw response body.
+}
+
+// ValidationError describes why a single property of a request was rejected.
+// Property is the path to the property, e.g. "recipient.name" or "lines.0.product".
+type ValidationError struct {
+	Property      string
+	ErrorCode     string
+	Message       string
+	DeveloperHint string
+	InputValue    any
+}
+
+// Error describes the failed request by its path, error code, message and
+// the properties that failed validation. The query of the endpoint and the
+// body are left out, as filters and the data e-conomic echoes back can hold
+// personal data; Endpoint and Body still have them.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "error calling e-conomic (%s %s => %d)", e.Method, logEndpoint(e.Endpoint), e.StatusCode)
+	if e.ErrorCode != "" {
+		b.WriteString(" " + e.ErrorCode)
+	}
+	if e.Message != "" {
+		b.WriteString(" " + e.Message)
+	}
+	for _, v := range e.Errors {
+		b.WriteString("; " + v.Property)
+		if v.ErrorCode != "" {
+			b.WriteString(": " + v.ErrorCode)
+		}
+	}
+	if e.LogId != "" {
+		fmt.Fprintf(&b, " (logId %s)", e.LogId)
+	}
+	return b.String()
+}
+
+// HasErrorCode reports whether the error or any of its validation errors
+// carries the given e-conomic error code.
+func (e *APIError) HasErrorCode(code string) bool {
+	if e.ErrorCode == code {
+		return true
+	}
+	for _, v := range e.Errors {
+		if v.ErrorCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		Body:       string(body),
+	}
+	e.parseBody(body)
+	return e
+}
+
+// restErrorBody is the error format of the REST API.
+type restErrorBody struct {
+	ErrorCode     string          `json:"errorCode"`
+	Message       string          `json:"message"`
+	DeveloperHint string          `json:"developerHint"`
+	LogId         string          `json:"logId"`
+	Errors        json.RawMessage `json:"errors"`
+}
+
+// problemBody is the RFC 7807 problem format used by the OpenAPI endpoints.
+type problemBody struct {
+	Title   string `json:"title"`
+	Detail  string `json:"detail"`
+	TraceId string `json:"traceId"`
+}
+
+func (e *APIError) parseBody(body []byte) {
+	var rest restErrorBody
+	if err := json.Unmarshal(body, &rest); err != nil {
+		return // not JSON; Body is all we have
+	}
+	e.ErrorCode = rest.ErrorCode
+	e.Message = rest.Message
+	e.DeveloperHint = rest.DeveloperHint
+	e.LogId = rest.LogId
+
+	var problem problemBody
+	json.Unmarshal(body, &problem)
+	if e.Message == "" {
+		e.Message = strings.TrimSpace(problem.Title + " " + problem.Detail)
+	}
+	if e.LogId == "" {
+		e.LogId = problem.TraceId
+	}
+
+	if len(rest.Errors) == 0 {
+		return
+	}
+	// The OpenAPI endpoints use {"property": ["message", ...]}.
+	var flat map[string][]string
+	if json.Unmarshal(rest.Errors, &flat) == nil {
+		properties := make([]string, 0, len(flat))
+		for p := range flat {
+			properties = append(properties, p)
+		}
+		sort.Strings(properties)
+		for _, p := range properties {
+			for _, msg := range flat[p] {
+				e.Errors = append(e.Errors, ValidationError{Property: p, Message: msg})
+			}
+		}
+		return
+	}
+	// The REST API nests {"property": {"errors": [...]}} following the shape
+	// of the request, so walk it and collect every "errors" array.
+	var nested any
+	if json.Unmarshal(rest.Errors, &nested) == nil {
+		e.Errors = collectValidationErrors("", nested, e.Errors)
+	}
+}
+
+func collectValidationErrors(path string, node any, acc []ValidationError) []ValidationError {
+	switch v := node.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if k == "errors" {
+				if list, ok := v[k].([]any); ok {
+					acc = appendValidationErrors(path, list, acc)
+					continue
+				}
+			}
+			child := k
+			if k == "items" {
+				child = "" // arrays are wrapped as {"items": [...]}
+			}
+			acc = collectValidationErrors(joinPath(path, child), v[k], acc)
+		}
+	case []any:
+		for i, item := range v {
+			acc = collectValidationErrors(joinPath(path, fmt.Sprint(i)), item, acc)
+		}
+	}
+	return acc
+}
+
+func appendValidationErrors(path string, list []any, acc []ValidationError) []ValidationError {
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		str := func(key string) string {
+			s, _ := m[key].(string)
+			return s
+		}
+		acc = append(acc, ValidationError{
+			Property:      path,
+			ErrorCode:     str("errorCode"),
+			Message:       str("errorMessage"),
+			DeveloperHint: str("developerHint"),
+			InputValue:    m["inputValue"],
+		})
+	}
+	return acc
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if name == "" {
+		return path
+	}
+	return path + "." + name
+}
+
+// AsAPIError returns the *APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+func hasStatus(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an e-conomic 404 response.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err says the entity already exists, either as a
+// 409 response or as a validation error with ErrorCodeAlreadyExists.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict) || HasErrorCode(err, ErrorCodeAlreadyExists)
+}
+
+// IsValidation reports whether e-conomic rejected the request body.
+func IsValidation(err error) bool {
+	if hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity) {
+		return true
+	}
+	apiErr, ok := AsAPIError(err)
+	return ok && len(apiErr.Errors) > 0
+}
+
+// IsUnauthorized reports whether the tokens were rejected.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// HasErrorCode reports whether err is an *APIError carrying the given code.
+func HasErrorCode(err error, code string) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.HasErrorCode(code)
+}
@@ -0,0 +1,71 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRestError(t *testing.T) {
+	body := `{"message":"Validation failed. 2 errors found.","errorCode":"E04300","developerHint":"Inspect validation errors and correct your request.","logId":"abc123","httpStatusCode":400,
+		"errors":{"customerNumber":{"errors":[{"propertyName":"customerNumber","errorMessage":"Customer number already in use.","errorCode":"E06010","inputValue":1}]},
+		"recipient":{"name":{"errors":[{"propertyName":"name","errorMessage":"Name is required.","errorCode":"E07010"}]}}}}`
+	err := fmt.Errorf("wrapped: %w", newAPIError(http.MethodPost, "customers", 400, []byte(body)))
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("Expected an APIError")
+	}
+	if apiErr.ErrorCode != "E04300" || apiErr.LogId != "abc123" || apiErr.DeveloperHint == "" {
+		t.Fatalf("Unexpected parse: %+v", apiErr)
+	}
+	if len(apiErr.Errors) != 2 {
+		t.Fatalf("Expected 2 validation errors, got %+v", apiErr.Errors)
+	}
+	if apiErr.Errors[0].Property != "customerNumber" || apiErr.Errors[1].Property != "recipient.name" {
+		t.Fatalf("Unexpected properties: %+v", apiErr.Errors)
+	}
+	if !IsValidation(err) || !IsConflict(err) || IsNotFound(err) {
+		t.Fatalf("Unexpected classification of %s", err)
+	}
+	// the message leaves out the query and the body, which can hold personal data
+	err = newAPIError(http.MethodGet, "customers?filter=email$eq:abe@example.com", 400,
+		[]byte(`{"message":"Invalid filter.","errorCode":"E00400","logId":"def456","developerHint":"abe@example.com is not valid here."}`))
+	expected := "error calling e-conomic (GET customers => 400) E00400 Invalid filter. (logId def456)"
+	if err.Error() != expected {
+		t.Fatalf("Expected %q, got %q", expected, err.Error())
+	}
+	if apiErr, _ := AsAPIError(err); !strings.Contains(apiErr.Body, "abe@example.com") || !strings.Contains(apiErr.Endpoint, "filter") {
+		t.Fatalf("Expected the body and endpoint to be kept, got %+v", apiErr)
+	}
+}
+
+func TestParseProblemError(t *testing.T) {
+	body := `{"type":"https://tools.ietf.org/html/rfc7231#section-6.5.1","title":"One or more validation errors occurred.","status":400,"traceId":"00-xyz","errors":{"Amount":["The Amount field is required."]}}`
+	apiErr := newAPIError(http.MethodPost, "/journalsapi/v14.0.1/draft-entries", 400, []byte(body))
+	if apiErr.LogId != "00-xyz" {
+		t.Fatalf("Expected traceId as LogId, got %s", apiErr.LogId)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Property != "Amount" {
+		t.Fatalf("Unexpected validation errors: %+v", apiErr.Errors)
+	}
+}
+
+func TestBothAPIsReturnAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found","errorCode":"E07000"}`))
+	}))
+	defer server.Close()
+	client := &Client{AgreementGrant: "grant", AppSecretToken: "secret", RestBaseURL: server.URL, OpenAPIBaseURL: server.URL}
+	err := client.callRestAPI(context.Background(), "customers/1", http.MethodGet, nil, nil)
+	if !IsNotFound(err) || !HasErrorCode(err, "E07000") {
+		t.Fatalf("Expected a not found APIError from the REST API, got %v", err)
+	}
+	err = client.callAPI(context.Background(), "/dimensionsapi/v5.3.0/values/1/1", http.MethodGet, nil, nil, nil)
+	if !IsNotFound(err) {
+		t.Fatalf("Expected a not found APIError from the OpenAPI, got %v", err)
+	}
+}
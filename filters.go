@@ -1,10 +1,12 @@
-// This is a work in progress.
-
 package economic
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type FilterOperator string
@@ -31,8 +33,309 @@ const (
 	FilterOperatorNotIn              = FilterOperator("$nin")
 )
 
+// filterNull is the value matching absent properties, as in "email$eq:$null".
+const filterNull = "$null"
+
+const filterEscapable = "$()*,[]"
+
+// EscapeFilterValue escapes the characters that have a meaning in a filter.
+func EscapeFilterValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(filterEscapable, s[i]) >= 0 {
+			b.WriteByte('$')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func unescapeFilterValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '$' && i+1 < len(s) && strings.IndexByte(filterEscapable, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// FilterExpr is a node of a filter expression: a Condition, a FilterGroup or
+// a negation made with Not. Build expressions with the helpers below, e.g.
+//
+//	And(Eq("currency", "DKK"), Or(Gt("balance", 0), IsNull("email")))
+type FilterExpr interface {
+	appendTo(b *strings.Builder, parent FilterOperator) error
+	negate() (FilterExpr, error)
+}
+
+// RenderFilter returns the filter string of expr.
+func RenderFilter(expr FilterExpr) (string, error) {
+	if expr == nil {
+		return "", nil
+	}
+	var b strings.Builder
+	if err := expr.appendTo(&b, ""); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Condition compares a property, e.g. "customer.customerNumber", with a
+// value. Values are rendered by type: strings are escaped, time.Time as a
+// date (or a timestamp if it has a time of day), numbers and bools as is, nil
+// as $null and slices as lists for $in/$nin.
+type Condition struct {
+	Field    string
+	Operator FilterOperator
+	Value    any
+}
+
+func (c Condition) appendTo(b *strings.Builder, _ FilterOperator) error {
+	b.WriteString(c.Field)
+	b.WriteString(string(c.Operator))
+	b.WriteByte(':')
+	if c.Operator == FilterOperatorIn || c.Operator == FilterOperatorNotIn {
+		b.WriteByte('[')
+		for i, v := range listValues(c.Value) {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(filterValue(v))
+		}
+		b.WriteByte(']')
+		return nil
+	}
+	if pattern, ok := c.Value.(string); ok && c.Operator == FilterOperatorSubstringMatch {
+		// keep "*" as the wildcard
+		b.WriteString(strings.ReplaceAll(EscapeFilterValue(pattern), "$*", "*"))
+		return nil
+	}
+	b.WriteString(filterValue(c.Value))
+	return nil
+}
+
+var negatedOperators = map[FilterOperator]FilterOperator{
+	FilterOperatorEquals:             FilterOperatorNotEquals,
+	FilterOperatorNotEquals:          FilterOperatorEquals,
+	FilterOperatorGreaterThan:        FilterOperatorLessThanOrEqual,
+	FilterOperatorGreaterThanOrEqual: FilterOperatorLessThan,
+	FilterOperatorLessThan:           FilterOperatorGreaterThanOrEqual,
+	FilterOperatorLessThanOrEqual:    FilterOperatorGreaterThan,
+	FilterOperatorIn:                 FilterOperatorNotIn,
+	FilterOperatorNotIn:              FilterOperatorIn,
+}
+
+func (c Condition) negate() (FilterExpr, error) {
+	op, ok := negatedOperators[c.Operator]
+	if !ok {
+		return nil, fmt.Errorf("filter operator %s cannot be negated", c.Operator)
+	}
+	c.Operator = op
+	return c, nil
+}
+
+// listValues returns the elements of a slice or array, or v itself.
+func listValues(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{v}
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values
+}
+
+func filterValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return filterNull
+	case likePattern:
+		return string(v)
+	case string:
+		return EscapeFilterValue(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case Amount:
+		return v.String()
+	case Date:
+		return v.String()
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return EscapeFilterValue(v.String())
+	}
+	return EscapeFilterValue(fmt.Sprint(v))
+}
+
+// FilterGroup joins expressions with $and or $or. Groups are parenthesised
+// where needed, $and binding tighter than $or.
+type FilterGroup struct {
+	Operator FilterOperator // FilterOperatorAndAlso or FilterOperatorOrElse
+	Exprs    []FilterExpr
+}
+
+func (g FilterGroup) appendTo(b *strings.Builder, parent FilterOperator) error {
+	if g.Operator != FilterOperatorAndAlso && g.Operator != FilterOperatorOrElse {
+		return fmt.Errorf("filter group operator must be $and or $or, not %q", g.Operator)
+	}
+	if len(g.Exprs) == 0 {
+		return fmt.Errorf("empty %s filter group", g.Operator)
+	}
+	if len(g.Exprs) == 1 {
+		return g.Exprs[0].appendTo(b, parent)
+	}
+	parens := parent == FilterOperatorAndAlso && g.Operator == FilterOperatorOrElse
+	if parens {
+		b.WriteByte('(')
+	}
+	for i, e := range g.Exprs {
+		if i > 0 {
+			b.WriteString(string(g.Operator))
+			b.WriteByte(':')
+		}
+		if err := e.appendTo(b, g.Operator); err != nil {
+			return err
+		}
+	}
+	if parens {
+		b.WriteByte(')')
+	}
+	return nil
+}
+
+func (g FilterGroup) negate() (FilterExpr, error) {
+	negated := FilterGroup{Operator: FilterOperatorAndAlso, Exprs: make([]FilterExpr, len(g.Exprs))}
+	if g.Operator == FilterOperatorAndAlso {
+		negated.Operator = FilterOperatorOrElse
+	}
+	for i, e := range g.Exprs {
+		n, err := e.negate()
+		if err != nil {
+			return nil, err
+		}
+		negated.Exprs[i] = n
+	}
+	return negated, nil
+}
+
+type notExpr struct {
+	expr FilterExpr
+}
+
+func (n notExpr) appendTo(b *strings.Builder, parent FilterOperator) error {
+	negated, err := n.expr.negate()
+	if err != nil {
+		return err
+	}
+	return negated.appendTo(b, parent)
+}
+
+func (n notExpr) negate() (FilterExpr, error) {
+	return n.expr, nil
+}
+
+// And matches when all exprs match.
+func And(exprs ...FilterExpr) FilterExpr {
+	return FilterGroup{Operator: FilterOperatorAndAlso, Exprs: exprs}
+}
+
+// Or matches when any of exprs matches.
+func Or(exprs ...FilterExpr) FilterExpr {
+	return FilterGroup{Operator: FilterOperatorOrElse, Exprs: exprs}
+}
+
+// Not matches when expr does not. The filter language has no negation, so it
+// is rendered by inverting the operators; $like cannot be negated.
+func Not(expr FilterExpr) FilterExpr {
+	return notExpr{expr: expr}
+}
+
+func Eq(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorEquals, Value: value}
+}
+
+func Ne(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorNotEquals, Value: value}
+}
+
+func Gt(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorGreaterThan, Value: value}
+}
+
+func Gte(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorGreaterThanOrEqual, Value: value}
+}
+
+func Lt(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorLessThan, Value: value}
+}
+
+func Lte(field string, value any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorLessThanOrEqual, Value: value}
+}
+
+// Like matches a case-insensitive pattern in which "*" is a wildcard.
+func Like(field string, pattern string) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorSubstringMatch, Value: pattern}
+}
+
+// likePattern is an already escaped $like pattern, as parsed by ParseFilter.
+type likePattern string
+
+// In matches any of values, given one by one or as a single slice.
+func In(field string, values ...any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorIn, Value: inValues(values)}
+}
+
+func NotIn(field string, values ...any) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorNotIn, Value: inValues(values)}
+}
+
+func inValues(values []any) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values
+}
+
+// IsNull matches when the property has no value.
+func IsNull(field string) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorEquals, Value: nil}
+}
+
+// NotNull matches when the property has a value.
+func NotNull(field string) FilterExpr {
+	return Condition{Field: field, Operator: FilterOperatorNotEquals, Value: nil}
+}
+
+// Filter is a filter string passed to e-conomic's filter query parameter.
+// Build it condition by condition with AndCondition/OrCondition, or from an
+// expression with NewFilter.
 type Filter struct {
 	filterStr string
+	err       error
+}
+
+// NewFilter renders expr. A rendering error is reported by Err and by the
+// calls the filter is passed to.
+func NewFilter(expr FilterExpr) *Filter {
+	s, err := RenderFilter(expr)
+	return &Filter{filterStr: s, err: err}
 }
 
 func (f *Filter) AndCondition(field string, operator FilterOperator, value any) {
@@ -44,9 +347,9 @@ func (f *Filter) OrCondition(field string, operator FilterOperator, value any) {
 }
 
 func (f *Filter) condition(field string, operator FilterOperator, value any, and bool) {
-	value = fmt.Sprint(value)
-	if operator == FilterOperatorIn || operator == FilterOperatorNotIn {
-		value = strings.ReplaceAll(fmt.Sprintf("%v", value), " ", ",")
+	s, err := RenderFilter(Condition{Field: field, Operator: operator, Value: value})
+	if err != nil && f.err == nil {
+		f.err = err
 	}
 	if len(f.filterStr) > 0 {
 		if and {
@@ -55,9 +358,162 @@ func (f *Filter) condition(field string, operator FilterOperator, value any, and
 			f.filterStr += "$or:"
 		}
 	}
-	f.filterStr += fmt.Sprintf("%s%s:%s", field, string(operator), fmt.Sprint(value))
+	f.filterStr += s
 }
 
 func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
 	return f.filterStr
 }
+
+// Err returns the error, if any, from rendering the filter.
+func (f *Filter) Err() error {
+	if f == nil {
+		return nil
+	}
+	return f.err
+}
+
+// Expr parses the filter back into an expression. A nil filter has none.
+func (f *Filter) Expr() (FilterExpr, error) {
+	if f == nil {
+		return nil, nil
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return ParseFilter(f.filterStr)
+}
+
+// ParseFilter parses a filter string. Conditions get string values (a
+// []string for $in/$nin and nil for $null), as the string carries no types.
+func ParseFilter(s string) (FilterExpr, error) {
+	if s == "" {
+		return nil, nil
+	}
+	p := &filterParser{s: s}
+	expr, err := p.parseGroup(FilterOperatorOrElse)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at position %d in filter", p.s[p.pos:], p.pos)
+	}
+	return expr, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+// parseGroup parses terms joined by op, where the terms of an $or group are
+// $and groups.
+func (p *filterParser) parseGroup(op FilterOperator) (FilterExpr, error) {
+	next := p.parseTerm
+	if op == FilterOperatorOrElse {
+		next = func() (FilterExpr, error) { return p.parseGroup(FilterOperatorAndAlso) }
+	}
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []FilterExpr{first}
+	sep := string(op) + ":"
+	for strings.HasPrefix(p.s[p.pos:], sep) {
+		p.pos += len(sep)
+		e, err := next()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return FilterGroup{Operator: op, Exprs: exprs}, nil
+}
+
+func (p *filterParser) parseTerm() (FilterExpr, error) {
+	if strings.HasPrefix(p.s[p.pos:], "(") {
+		p.pos++
+		expr, err := p.parseGroup(FilterOperatorOrElse)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(p.s[p.pos:], ")") {
+			return nil, fmt.Errorf("missing ) at position %d in filter", p.pos)
+		}
+		p.pos++
+		return expr, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != '$' {
+		p.pos++
+	}
+	field := p.s[start:p.pos]
+	if field == "" || p.pos == len(p.s) {
+		return nil, fmt.Errorf("expected field and operator at position %d in filter", start)
+	}
+	end := strings.IndexByte(p.s[p.pos:], ':')
+	if end < 0 {
+		return nil, fmt.Errorf("missing : after operator at position %d in filter", p.pos)
+	}
+	operator := FilterOperator(p.s[p.pos : p.pos+end])
+	if _, ok := negatedOperators[operator]; !ok && operator != FilterOperatorSubstringMatch {
+		return nil, fmt.Errorf("unknown filter operator %s at position %d", operator, p.pos)
+	}
+	p.pos += end + 1
+	raw := p.readValue()
+	c := Condition{Field: field, Operator: operator}
+	switch {
+	case raw == filterNull:
+	case operator == FilterOperatorIn || operator == FilterOperatorNotIn:
+		if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("expected [list] after %s at position %d in filter", operator, p.pos-len(raw))
+		}
+		c.Value = splitFilterList(raw[1 : len(raw)-1])
+	case operator == FilterOperatorSubstringMatch:
+		c.Value = likePattern(raw)
+	default:
+		c.Value = unescapeFilterValue(raw)
+	}
+	return c, nil
+}
+
+// readValue reads up to the next unescaped "$and:", "$or:" or ")".
+func (p *filterParser) readValue() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		rest := p.s[p.pos:]
+		if strings.HasPrefix(rest, "$and:") || strings.HasPrefix(rest, "$or:") || rest[0] == ')' {
+			break
+		}
+		if rest[0] == '$' && len(rest) > 1 && strings.IndexByte(filterEscapable, rest[1]) >= 0 {
+			p.pos++
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// splitFilterList splits a list on unescaped commas.
+func splitFilterList(raw string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	var values []string
+	start := 0
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '$' && i+1 < len(raw) && strings.IndexByte(filterEscapable, raw[i+1]) >= 0:
+			i++
+		case raw[i] == ',':
+			values = append(values, unescapeFilterValue(raw[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescapeFilterValue(raw[start:]))
+}
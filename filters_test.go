@@ -1,6 +1,10 @@
 package economic
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestFilter(t *testing.T) {
 	// Simple test to check if the filter string is created correctly
@@ -19,5 +23,71 @@ func TestFilter(t *testing.T) {
 	if fArr.String() != expectedArr {
 		t.Errorf("Expected %s, got %s", expectedArr, fArr)
 	}
-	//TODO: Nesting using $and:()
+}
+
+func TestFilterExpressions(t *testing.T) {
+	date := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr     FilterExpr
+		expected string
+	}{
+		{And(Eq("currency", "DKK"), Or(Gt("balance", 10.5), IsNull("email"))), `currency$eq:DKK$and:(balance$gt:10.5$or:email$eq:$null)`},
+		{Or(And(Eq("a", 1), Eq("b", true)), NotNull("c")), `a$eq:1$and:b$eq:true$or:c$ne:$null`},
+		{Eq("name", "Smith & Co (ApS), 50$*"), `name$eq:Smith & Co $(ApS$)$, 50$$$*`},
+		{In("name", "a b", "c,d"), `name$in:[a b,c$,d]`},
+		{In("number", []int{1, 2}), `number$in:[1,2]`},
+		{Like("name", "ab*(x)"), `name$like:ab*$(x$)`},
+		{Gte("date", date), `date$gte:2024-06-01`},
+		{Not(And(Eq("a", 1), In("b", 2, 3))), `a$ne:1$or:b$nin:[2,3]`},
+		{And(Eq("a", 1), Not(Or(Gt("b", 2), IsNull("c")))), `a$eq:1$and:b$lte:2$and:c$ne:$null`},
+	}
+	for _, test := range tests {
+		s, err := RenderFilter(test.expr)
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if s != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, s)
+		}
+		parsed, err := ParseFilter(s)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %s", s, err)
+		}
+		again, err := RenderFilter(parsed)
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if again != s {
+			t.Errorf("Expected %s to survive parsing, got %s", s, again)
+		}
+	}
+	if _, err := RenderFilter(Not(Like("name", "a*"))); err == nil {
+		t.Errorf("Expected an error negating $like")
+	}
+	if err := NewFilter(Not(Like("name", "a*"))).Err(); err == nil {
+		t.Errorf("Expected the filter to keep the error")
+	}
+	var none *Filter
+	if expr, err := none.Expr(); expr != nil || err != nil {
+		t.Errorf("Expected no expression for a nil filter, got %v, %v", expr, err)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	expr, err := ParseFilter(`name$eq:a$$b$and:(x$in:[1,2$,3]$or:email$eq:$null)`)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	expected := And(
+		Condition{Field: "name", Operator: FilterOperatorEquals, Value: "a$b"},
+		Or(Condition{Field: "x", Operator: FilterOperatorIn, Value: []string{"1", "2,3"}}, IsNull("email")),
+	)
+	if !reflect.DeepEqual(expr, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, expr)
+	}
+	for _, bad := range []string{"name", "name$eq", "(name$eq:a", "name$eq:a)", "name$foo:a", "name$in:a"} {
+		if _, err := ParseFilter(bad); err == nil {
+			t.Errorf("Expected an error parsing %s", bad)
+		}
+	}
 }
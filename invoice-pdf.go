@@ -0,0 +1,190 @@
+package economic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func (client *Client) DownloadDraftInvoicePdf(invoiceNo int, w io.Writer) error {
+	return client.DownloadDraftInvoicePdfContext(context.Background(), invoiceNo, w)
+}
+
+func (client *Client) DownloadDraftInvoicePdfContext(ctx context.Context, invoiceNo int, w io.Writer) error {
+	_, err := client.download(ctx, fmt.Sprintf("invoices/drafts/%d/pdf", invoiceNo), w)
+	return err
+}
+
+func (client *Client) DownloadBookedInvoicePdf(invoiceNo int, w io.Writer) error {
+	return client.DownloadBookedInvoicePdfContext(context.Background(), invoiceNo, w)
+}
+
+func (client *Client) DownloadBookedInvoicePdfContext(ctx context.Context, invoiceNo int, w io.Writer) error {
+	_, err := client.download(ctx, fmt.Sprintf("invoices/booked/%d/pdf", invoiceNo), w)
+	return err
+}
+
+// DownloadPdf streams the PDF behind a link such as Invoice.Pdf.Download or
+// Order.Pdf.Download to w.
+func (client *Client) DownloadPdf(pdf *Pdf, w io.Writer) error {
+	return client.DownloadPdfContext(context.Background(), pdf, w)
+}
+
+func (client *Client) DownloadPdfContext(ctx context.Context, pdf *Pdf, w io.Writer) error {
+	if pdf == nil || pdf.Download == "" {
+		return fmt.Errorf("no pdf link")
+	}
+	endpoint, err := client.restEndpoint(pdf.Download)
+	if err != nil {
+		return err
+	}
+	_, err = client.download(ctx, endpoint, w)
+	return err
+}
+
+// InvoiceArchive is the manifest ArchiveBookedInvoices writes next to the
+// PDFs. It lists every booked invoice of the window with the checksum of its
+// PDF, so the archive can be verified later.
+type InvoiceArchive struct {
+	From     Date              `json:"from"` // First invoice date in the window.
+	To       Date              `json:"to"`   // Last invoice date in the window.
+	Invoices []ArchivedInvoice `json:"invoices"`
+}
+
+type ArchivedInvoice struct {
+	BookedInvoiceNumber int    `json:"bookedInvoiceNumber"`
+	Date                Date   `json:"date"`
+	CustomerNumber      int    `json:"customerNumber"`
+	Currency            string `json:"currency"`
+	GrossAmount         Amount `json:"grossAmount"`
+	File                string `json:"file"` // Name of the PDF, relative to the archive directory.
+	Size                int64  `json:"size"`
+	SHA256              string `json:"sha256"` // Hex encoded SHA-256 of the PDF.
+}
+
+// ArchiveFileName is the name under which ArchiveBookedInvoices stores the
+// PDF of a booked invoice. Names sort by date and then invoice number.
+func ArchiveFileName(invoice Invoice) string {
+	return fmt.Sprintf("%s-invoice-%d.pdf", invoice.Date, invoice.BookedInvoiceNumber)
+}
+
+// ArchiveManifestName is the name of the manifest of the archive of window.
+func ArchiveManifestName(window TimeWindow) string {
+	return fmt.Sprintf("manifest-%s-%s.json", window.From.Format("2006-01-02"), window.To.Format("2006-01-02"))
+}
+
+func (client *Client) ArchiveBookedInvoices(window TimeWindow, dir string) (*InvoiceArchive, error) {
+	return client.ArchiveBookedInvoicesContext(context.Background(), window, dir)
+}
+
+// ArchiveBookedInvoicesContext downloads the PDFs of all invoices booked with
+// a date in window to dir, and writes a manifest (see ArchiveManifestName).
+// Booked invoices cannot change, so PDFs already in dir are kept, and an
+// interrupted run can simply be started again.
+func (client *Client) ArchiveBookedInvoicesContext(ctx context.Context, window TimeWindow, dir string) (*InvoiceArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	filter := &Filter{}
+	filter.AndCondition("date", FilterOperatorGreaterThanOrEqual, window.From.Format("2006-01-02"))
+	filter.AndCondition("date", FilterOperatorLessThanOrEqual, window.To.Format("2006-01-02"))
+	tc := &TypedClient[Invoice]{client: client}
+	invoices, err := tc.getEntities(ctx, "invoices/booked", ListOptions{PageSize: invoicePageSize, Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(invoices, func(i, j int) bool {
+		return invoices[i].BookedInvoiceNumber < invoices[j].BookedInvoiceNumber
+	})
+
+	archive := &InvoiceArchive{
+		From:     DateOf(window.From),
+		To:       DateOf(window.To),
+		Invoices: []ArchivedInvoice{},
+	}
+	for _, invoice := range invoices {
+		name := ArchiveFileName(invoice)
+		size, sum, err := client.archivePdf(ctx, invoice.BookedInvoiceNumber, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("archiving booked invoice %d: %w", invoice.BookedInvoiceNumber, err)
+		}
+		entry := ArchivedInvoice{
+			BookedInvoiceNumber: invoice.BookedInvoiceNumber,
+			Date:                invoice.Date,
+			CustomerNumber:      invoice.CustomerNumber,
+			Currency:            invoice.Currency,
+			GrossAmount:         invoice.GrossAmount,
+			File:                name,
+			Size:                size,
+			SHA256:              sum,
+		}
+		if invoice.Customer != nil {
+			entry.CustomerNumber = invoice.Customer.CustomerNumber
+		}
+		archive.Invoices = append(archive.Invoices, entry)
+	}
+
+	manifest, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, ArchiveManifestName(window)), func(w io.Writer) error {
+		_, err := w.Write(append(manifest, '\n'))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// archivePdf downloads the PDF of a booked invoice to path unless it is
+// already there, and returns its size and checksum.
+func (client *Client) archivePdf(ctx context.Context, invoiceNo int, path string) (int64, string, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		err = writeFileAtomic(path, func(w io.Writer) error {
+			return client.DownloadBookedInvoicePdfContext(ctx, invoiceNo, w)
+		})
+		if err != nil {
+			return 0, "", err
+		}
+	} else if err != nil {
+		return 0, "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeFileAtomic writes path through a temporary file in the same
+// directory, so a failed or interrupted write never leaves a partial file.
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,89 @@
+package economic
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloadInvoicePdf(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	draft, err := client.CreateInvoice(testOrder(""))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	var buf bytes.Buffer
+	srv.FailNext(http.MethodGet, "invoices/drafts", http.StatusServiceUnavailable)
+	if err := client.DownloadDraftInvoicePdf(draft.DraftInvoiceNumber, &buf); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF")) {
+		t.Fatalf("Expected a PDF, got %q", buf.String())
+	}
+	buf.Reset()
+	if err := client.DownloadPdf(draft.Pdf, &buf); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF")) {
+		t.Fatalf("Expected a PDF from the link, got %q", buf.String())
+	}
+	if err := client.DownloadPdf(&Pdf{Download: "https://example.com/invoices/drafts/1/pdf"}, &buf); err == nil {
+		t.Fatalf("Expected links to other hosts to be refused")
+	}
+	if err := client.DownloadBookedInvoicePdf(999, &buf); !IsNotFound(err) {
+		t.Fatalf("Expected not found, got %v", err)
+	}
+}
+
+func TestArchiveBookedInvoices(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("invoices/booked",
+		Invoice{BookedInvoiceNumber: 12, Date: MustParseDate("2024-03-02"), Currency: "DKK", Customer: &Customer{CustomerNumber: 1}},
+		Invoice{BookedInvoiceNumber: 11, Date: MustParseDate("2024-03-01"), Currency: "DKK", Customer: &Customer{CustomerNumber: 2}},
+		Invoice{BookedInvoiceNumber: 13, Date: MustParseDate("2024-04-01"), Currency: "DKK"},
+	)
+	window := TimeWindow{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	dir := t.TempDir()
+	archive, err := client.ArchiveBookedInvoices(window, dir)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(archive.Invoices) != 2 || archive.Invoices[0].File != "2024-03-01-invoice-11.pdf" || archive.Invoices[1].CustomerNumber != 1 {
+		t.Fatalf("Expected invoices 11 and 12, got %+v", archive.Invoices)
+	}
+	for _, invoice := range archive.Invoices {
+		info, err := os.Stat(filepath.Join(dir, invoice.File))
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		if info.Size() != invoice.Size || len(invoice.SHA256) != 64 {
+			t.Fatalf("Expected size and checksum in the manifest, got %+v", invoice)
+		}
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "manifest-2024-03-01-2024-03-31.json"))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	var stored InvoiceArchive
+	if err := json.Unmarshal(manifest, &stored); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(stored.Invoices) != 2 {
+		t.Fatalf("Expected 2 invoices in the manifest, got %+v", stored)
+	}
+
+	downloads := countRequests(srv, http.MethodGet, "invoices/booked/11/pdf")
+	if _, err := client.ArchiveBookedInvoices(window, dir); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if n := countRequests(srv, http.MethodGet, "invoices/booked/11/pdf"); n != downloads {
+		t.Fatalf("Expected archived PDFs not to be downloaded again, got %d requests", n)
+	}
+}
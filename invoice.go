@@ -4,18 +4,18 @@ package economic
 type Invoice struct {
 	BookedInvoiceNumber            int           `json:"bookedInvoiceNumber"`       // A reference number for the booked invoice document.
 	DraftInvoiceNumber             int           `json:"draftInvoiceNumber"`        // A reference number for the draft invoice document.
-	Date                           string        `json:"date"`                      // Invoice issue date. Format according to ISO-8601 (YYYY-MM-DD).
+	Date                           Date          `json:"date"`                      // Invoice issue date. Format according to ISO-8601 (YYYY-MM-DD).
 	Currency                       string        `json:"currency"`                  // The ISO 4217 currency code of the invoice.
 	ExchangeRate                   float64       `json:"exchangeRate"`              // The exchange rate between the invoice currency and the base currency of the agreement. The exchange rate expresses how much it will cost in base currency to buy 100 units of the invoice currency.
-	NetAmount                      float64       `json:"netAmount"`                 // The total invoice amount in the invoice currency before all taxes and discounts have been applied. For a credit note this amount will be negative.
-	NetAmountInBaseCurrency        float64       `json:"netAmountInBaseCurrency"`   // The total invoice amount in the base currency of the agreement before all taxes and discounts have been applied. For a credit note this amount will be negative.
-	GrossAmount                    float64       `json:"grossAmount"`               // The total invoice amount in the invoice currency after all taxes and discounts have been applied. For a credit note this amount will be negative.
-	GrossAmountInBaseCurrency      float64       `json:"grossAmountInBaseCurrency"` // The total invoice amount in the base currency of the agreement after all taxes and discounts have been applied. For a credit note this amount will be negative.
-	VatAmount                      float64       `json:"vatAmount"`                 // The total amount of VAT on the invoice in the invoice currency. This will have the same sign as net amount.
-	RoundingAmount                 float64       `json:"roundingAmount"`            // The total rounding error, if any, on the invoice in base currency.
-	Remainder                      float64       `json:"remainder"`                 // Remaining amount to be paid.
-	RemainderInBaseCurrency        float64       `json:"remainderInBaseCurrency"`   // Remaining amount to be paid in base currency.
-	DueDate                        string        `json:"dueDate"`                   // The date the invoice is due for payment. Only used if the terms of payment is of type 'duedate', in which case it is mandatory. Format according to ISO-8601 (YYYY-MM-DD).
+	NetAmount                      Amount        `json:"netAmount"`                 // The total invoice amount in the invoice currency before all taxes and discounts have been applied. For a credit note this amount will be negative.
+	NetAmountInBaseCurrency        Amount        `json:"netAmountInBaseCurrency"`   // The total invoice amount in the base currency of the agreement before all taxes and discounts have been applied. For a credit note this amount will be negative.
+	GrossAmount                    Amount        `json:"grossAmount"`               // The total invoice amount in the invoice currency after all taxes and discounts have been applied. For a credit note this amount will be negative.
+	GrossAmountInBaseCurrency      Amount        `json:"grossAmountInBaseCurrency"` // The total invoice amount in the base currency of the agreement after all taxes and discounts have been applied. For a credit note this amount will be negative.
+	VatAmount                      Amount        `json:"vatAmount"`                 // The total amount of VAT on the invoice in the invoice currency. This will have the same sign as net amount.
+	RoundingAmount                 Amount        `json:"roundingAmount"`            // The total rounding error, if any, on the invoice in base currency.
+	Remainder                      Amount        `json:"remainder"`                 // Remaining amount to be paid.
+	RemainderInBaseCurrency        Amount        `json:"remainderInBaseCurrency"`   // Remaining amount to be paid in base currency.
+	DueDate                        Date          `json:"dueDate"`                   // The date the invoice is due for payment. Only used if the terms of payment is of type 'duedate', in which case it is mandatory. Format according to ISO-8601 (YYYY-MM-DD).
 	PaymentTermsNumber             int           `json:"paymentTermsNumber"`        // A unique identifier of the payment term.
 	DaysOfCredit                   int           `json:"daysOfCredit"`              // The number of days of credit on the invoice. This field is only valid if terms of payment is not of type 'duedate'.
 	PaymentTerms                   *PaymentTerms `json:"paymentTerms"`
@@ -36,6 +36,7 @@ type Invoice struct {
 	Layout                         *Layout       `json:"layout"`
 	ProjectNumber                  int           `json:"projectNumber"`                  // A unique identifier of the project.
 	ProjectSelf                    string        `json:"projectSelf"`                    // A unique reference to the project resource.
+	Project                        *Project      `json:"project,omitempty"`              // The project the invoice is connected to, as returned by the REST API.
 	Lines                          []OrderLine   `json:"lines"`                          // The line number is a unique number within the invoice.
 	UnitNumber                     int           `json:"unitNumber"`                     // The unique identifier of the unit.
 	UnitName                       string        `json:"unitName"`                       // The name of the unit (e.g. 'kg' for weight or 'l' for volume).
@@ -1,9 +1,8 @@
 package economic
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"time"
@@ -28,6 +27,10 @@ func YesterdayWindow() TimeWindow {
 }
 
 func (client *Client) GetJournalEntries(journalNumber int, windows ...TimeWindow) ([]JournalEntry, error) {
+	return client.GetJournalEntriesContext(context.Background(), journalNumber, windows...)
+}
+
+func (client *Client) GetJournalEntriesContext(ctx context.Context, journalNumber int, windows ...TimeWindow) ([]JournalEntry, error) {
 	window := YesterdayWindow()
 	if len(windows) > 0 {
 		window = windows[0]
@@ -43,14 +46,14 @@ func (client *Client) GetJournalEntries(journalNumber int, windows ...TimeWindow
 	draftParams := url.Values{"filter": {draftFilter}}
 	bookedParams := url.Values{"filter": {dateFilter}}
 
-	draft, err := getAllCursor[JournalEntry](client, journalDraftEntryBaseUrl, draftParams)
+	draft, err := getAllCursor[JournalEntry](ctx, client, journalDraftEntryBaseUrl, draftParams)
 	if err != nil {
 		return nil, err
 	}
 	if len(draft) > 0 {
-		log.Printf("GetJournalEntries: %d draft entries found", len(draft))
+		client.logger().Debug("GetJournalEntries: draft entries found", "count", len(draft))
 	}
-	booked, err := getAllCursor[JournalEntry](client, bookedEntriesApiBaseUrl, bookedParams)
+	booked, err := getAllCursor[JournalEntry](ctx, client, bookedEntriesApiBaseUrl, bookedParams)
 	if err != nil {
 		return nil, err
 	}
@@ -64,24 +67,27 @@ func (client *Client) GetJournalEntries(journalNumber int, windows ...TimeWindow
 		booked = filtered
 	}
 	if len(booked) > 0 {
-		log.Printf("GetJournalEntries: %d booked entries found", len(booked))
+		client.logger().Debug("GetJournalEntries: booked entries found", "count", len(booked))
 	}
 	return append(draft, booked...), nil
 }
 
 type JournalEntry struct {
-	EntryTypeNumber     int         `json:"entryTypeNumber"`
-	VoucherNumber       int         `json:"voucherNumber"`
-	JournalNumber       int         `json:"journalNumber"`
-	Date                string      `json:"date"`
-	Amount              json.Number `json:"amount"`
-	Currency            string      `json:"currency"`
-	EntryNumber         int         `json:"entryNumber,omitempty"`
-	AccountNumber       int         `json:"accountNumber"`
-	ContraAccountNumber int         `json:"contraAccountNumber,omitempty"`
-	Text                string      `json:"text,omitempty"`
-	VatCode             string      `json:"vatCode,omitempty"`
-	ContraVatCode       string      `json:"contraVatCode,omitempty"`
+	EntryTypeNumber     int     `json:"entryTypeNumber"`
+	VoucherNumber       int     `json:"voucherNumber"`
+	JournalNumber       int     `json:"journalNumber"`
+	Date                Date    `json:"date"`
+	Amount              Amount  `json:"amount"`
+	Currency            string  `json:"currency"`
+	EntryNumber         int     `json:"entryNumber,omitempty"`
+	AccountNumber       int     `json:"accountNumber,omitempty"`
+	ContraAccountNumber int     `json:"contraAccountNumber,omitempty"`
+	Text                string  `json:"text,omitempty"`
+	VatCode             string  `json:"vatCode,omitempty"`
+	ContraVatCode       string  `json:"contraVatCode,omitempty"`
+	ExchangeRate        float64 `json:"exchangeRate,omitempty"`   // Base currency per 100 units of Currency.
+	CustomerNumber      int     `json:"customerNumber,omitempty"` // The customer of a customer payment.
+	InvoiceNumber       int     `json:"invoiceNumber,omitempty"`  // The booked invoice a customer payment settles.
 }
 
 func truncateEntryText(j *JournalEntry) {
@@ -96,12 +102,21 @@ func truncateEntryText(j *JournalEntry) {
 // If the entry is created successfully, the EntryNumber field will be set.
 // Credits use negative amounts.
 func (client *Client) CreateJournalEntry(j *JournalEntry) error {
+	return client.CreateJournalEntryContext(context.Background(), j)
+}
+
+func (client *Client) CreateJournalEntryContext(ctx context.Context, j *JournalEntry) error {
+	return client.createJournalEntry(ctx, j, nil)
+}
+
+// createJournalEntry creates j. The retry lookup ignores the draft entries
+// in known, so identical lines of one voucher are each created.
+func (client *Client) createJournalEntry(ctx context.Context, j *JournalEntry, known map[int]bool) error {
 	resp := map[string]any{}
 	truncateEntryText(j)
-	err := client.callAPI(journalDraftEntryBaseUrl, http.MethodPost, nil, j, &resp)
+	err := client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodPost, nil, j, &resp, client.draftEntryLookup(j, resp, known))
 	if err == nil {
 		entryNumber := resp["entryNumber"]
-		log.Printf("entryNumber: %#v", entryNumber)
 		if entryNumber != nil {
 			j.EntryNumber = int(entryNumber.(float64))
 		}
@@ -109,13 +124,47 @@ func (client *Client) CreateJournalEntry(j *JournalEntry) error {
 	return err
 }
 
+// draftEntryLookup finds the draft entry a failed CreateJournalEntry made,
+// by its voucher number. Entries without a voucher number are not retried
+// after a 5xx or network error.
+func (client *Client) draftEntryLookup(j *JournalEntry, resp map[string]any, known map[int]bool) lookupFunc {
+	if j.VoucherNumber == 0 {
+		return nil
+	}
+	return func(ctx context.Context) (bool, error) {
+		entries, err := client.GetDraftEntriesByVoucherNumberContext(ctx, j.VoucherNumber)
+		if err != nil {
+			return false, err
+		}
+		for _, e := range entries {
+			if known[e.EntryNumber] {
+				continue
+			}
+			if e.JournalNumber == j.JournalNumber && e.Date == j.Date && e.Amount == j.Amount &&
+				e.AccountNumber == j.AccountNumber && e.ContraAccountNumber == j.ContraAccountNumber && e.Text == j.Text {
+				resp["entryNumber"] = float64(e.EntryNumber)
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
 func (client *Client) DeleteJournalEntry(j *JournalEntry) error {
-	return client.callAPI(fmt.Sprintf("%s/%d", journalDraftEntryBaseUrl, j.EntryNumber), http.MethodDelete, nil, nil, nil)
+	return client.DeleteJournalEntryContext(context.Background(), j)
+}
+
+func (client *Client) DeleteJournalEntryContext(ctx context.Context, j *JournalEntry) error {
+	return client.callAPI(ctx, fmt.Sprintf("%s/%d", journalDraftEntryBaseUrl, j.EntryNumber), http.MethodDelete, nil, nil, nil)
 }
 
 func (client *Client) GetDraftEntriesCount() (int, error) {
+	return client.GetDraftEntriesCountContext(context.Background())
+}
+
+func (client *Client) GetDraftEntriesCountContext(ctx context.Context) (int, error) {
 	var count int
-	err := client.callAPI(journalDraftEntryBaseUrl+"/count", http.MethodGet, nil, nil, &count)
+	err := client.callAPI(ctx, journalDraftEntryBaseUrl+"/count", http.MethodGet, nil, nil, &count)
 	if err != nil {
 		return 0, err
 	}
@@ -123,8 +172,12 @@ func (client *Client) GetDraftEntriesCount() (int, error) {
 }
 
 func (client *Client) GetCashPaymentById(id int) (JournalEntry, error) {
+	return client.GetCashPaymentByIdContext(context.Background(), id)
+}
+
+func (client *Client) GetCashPaymentByIdContext(ctx context.Context, id int) (JournalEntry, error) {
 	je := JournalEntry{}
-	jes, err := client.GetCashPaymentsById(id)
+	jes, err := client.GetCashPaymentsByIdContext(ctx, id)
 	if err != nil {
 		return je, err
 	}
@@ -133,30 +186,38 @@ func (client *Client) GetCashPaymentById(id int) (JournalEntry, error) {
 
 // GetAllJournalEntriesByVoucherNumber fetches all draft and booked entries for a voucher number across all time.
 func (client *Client) GetAllJournalEntriesByVoucherNumber(voucherNumber int) ([]JournalEntry, error) {
+	return client.GetAllJournalEntriesByVoucherNumberContext(context.Background(), voucherNumber)
+}
+
+func (client *Client) GetAllJournalEntriesByVoucherNumberContext(ctx context.Context, voucherNumber int) ([]JournalEntry, error) {
 	params := url.Values{"filter": {fmt.Sprintf("voucherNumber$eq:%d", voucherNumber)}}
 	draft := ItemsReponse[JournalEntry]{}
-	if err := client.callAPI(journalDraftEntryBaseUrl, http.MethodGet, params, nil, &draft); err != nil {
+	if err := client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodGet, params, nil, &draft); err != nil {
 		return nil, err
 	}
 	booked := ItemsReponse[JournalEntry]{}
-	if err := client.callAPI(bookedEntriesApiBaseUrl, http.MethodGet, params, nil, &booked); err != nil {
+	if err := client.callAPI(ctx, bookedEntriesApiBaseUrl, http.MethodGet, params, nil, &booked); err != nil {
 		return nil, err
 	}
 	for _, e := range booked.Items {
-		log.Printf("GetAllJournalEntriesByVoucherNumber: booked entry voucherNumber=%d amount=%s journalNumber=%d", e.VoucherNumber, e.Amount, e.JournalNumber)
+		client.logger().Debug("GetAllJournalEntriesByVoucherNumber: booked entry", "voucherNumber", e.VoucherNumber, "amount", e.Amount.String(), "journalNumber", e.JournalNumber)
 	}
 	return append(draft.Items, booked.Items...), nil
 }
 
 func (client *Client) GetCashPaymentsById(id int) ([]JournalEntry, error) {
+	return client.GetCashPaymentsByIdContext(context.Background(), id)
+}
+
+func (client *Client) GetCashPaymentsByIdContext(ctx context.Context, id int) ([]JournalEntry, error) {
 	jes := []JournalEntry{}
 	resp := ItemsReponse[JournalEntry]{}
 	params := url.Values{
 		"filter": {fmt.Sprintf("voucherNumber$eq:%d", id)},
 	}
-	err := client.callAPI(journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
+	err := client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
 	if err != nil {
-		log.Printf("Error: %s", err)
+		client.logger().Error("error getting draft entries", "voucherNumber", id, "error", err)
 	}
 	if len(resp.Items) == 0 {
 		return jes, fmt.Errorf("no payment with id %d", id)
@@ -171,8 +232,12 @@ func (client *Client) GetCashPaymentsById(id int) ([]JournalEntry, error) {
 //
 // If you need to credit the payment fill in the remaining fields and use a negative amount.
 func (client *Client) GetBookedCashPaymentById(id int) (JournalEntry, error) {
+	return client.GetBookedCashPaymentByIdContext(context.Background(), id)
+}
+
+func (client *Client) GetBookedCashPaymentByIdContext(ctx context.Context, id int) (JournalEntry, error) {
 	je := JournalEntry{}
-	jes, err := client.GetBookedCashPaymentsById(id)
+	jes, err := client.GetBookedCashPaymentsByIdContext(ctx, id)
 	if err != nil {
 		return je, err
 	}
@@ -180,14 +245,18 @@ func (client *Client) GetBookedCashPaymentById(id int) (JournalEntry, error) {
 }
 
 func (client *Client) GetBookedCashPaymentsById(id int) ([]JournalEntry, error) {
+	return client.GetBookedCashPaymentsByIdContext(context.Background(), id)
+}
+
+func (client *Client) GetBookedCashPaymentsByIdContext(ctx context.Context, id int) ([]JournalEntry, error) {
 	jes := []JournalEntry{}
 	resp := ItemsReponse[JournalEntry]{}
 	params := url.Values{
 		"filter": {fmt.Sprintf("voucherNumber$eq:%d", id)},
 	}
-	err := client.callAPI(bookedEntriesApiBaseUrl, http.MethodGet, params, nil, &resp)
+	err := client.callAPI(ctx, bookedEntriesApiBaseUrl, http.MethodGet, params, nil, &resp)
 	if err != nil {
-		log.Printf("Error: %s", err)
+		client.logger().Error("error getting booked entries", "voucherNumber", id, "error", err)
 	}
 	if len(resp.Items) == 0 {
 		return jes, fmt.Errorf("no payment with id %d", id)
@@ -198,11 +267,15 @@ func (client *Client) GetBookedCashPaymentsById(id int) ([]JournalEntry, error)
 // GetDraftEntriesByVoucherNumber returns all draft entries with the given voucher number.
 // Returns an empty slice and no error if none are found.
 func (client *Client) GetDraftEntriesByVoucherNumber(voucherNumber int) ([]JournalEntry, error) {
+	return client.GetDraftEntriesByVoucherNumberContext(context.Background(), voucherNumber)
+}
+
+func (client *Client) GetDraftEntriesByVoucherNumberContext(ctx context.Context, voucherNumber int) ([]JournalEntry, error) {
 	resp := ItemsReponse[JournalEntry]{}
 	params := url.Values{
 		"filter": {fmt.Sprintf("voucherNumber$eq:%d", voucherNumber)},
 	}
-	err := client.callAPI(journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
+	err := client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -211,30 +284,41 @@ func (client *Client) GetDraftEntriesByVoucherNumber(voucherNumber int) ([]Journ
 
 // UpdateJournalEntry updates an existing draft entry using PUT. Needs an entryNumber (returned from GetDraftEntriesByVoucherNumber).
 func (client *Client) UpdateJournalEntry(j *JournalEntry) error {
+	return client.UpdateJournalEntryContext(context.Background(), j)
+}
+
+func (client *Client) UpdateJournalEntryContext(ctx context.Context, j *JournalEntry) error {
 	truncateEntryText(j)
-	return client.callAPI(journalDraftEntryBaseUrl, http.MethodPut, nil, j, nil)
+	return client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodPut, nil, j, nil)
 }
 
 func (client *Client) BookAllEntries(journalNumber int) error {
-	return client.callAPI(fmt.Sprintf("/journalsapi/%s/journals/%d/book", journalApiVersion, journalNumber), http.MethodPost, nil, nil, nil)
+	return client.BookAllEntriesContext(context.Background(), journalNumber)
+}
+
+func (client *Client) BookAllEntriesContext(ctx context.Context, journalNumber int) error {
+	return client.callAPI(ctx, fmt.Sprintf("/journalsapi/%s/journals/%d/book", journalApiVersion, journalNumber), http.MethodPost, nil, nil, nil)
 }
 
-func (client *Client) GetJournalBalanceById(id int) (float64, error) {
+func (client *Client) GetJournalBalanceById(id int) (Amount, error) {
+	return client.GetJournalBalanceByIdContext(context.Background(), id)
+}
+
+// GetJournalBalanceByIdContext sums the draft entries with voucher number
+// id. A balanced voucher sums to zero.
+func (client *Client) GetJournalBalanceByIdContext(ctx context.Context, id int) (Amount, error) {
 	resp := ItemsReponse[JournalEntry]{}
 	params := url.Values{
 		"filter": {fmt.Sprintf("voucherNumber$eq:%d", id)},
 	}
-	err := client.callAPI(journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
+	err := client.callAPI(ctx, journalDraftEntryBaseUrl, http.MethodGet, params, nil, &resp)
 	if err != nil {
-		log.Printf("Error: %s", err)
+		client.logger().Error("error getting draft entries", "voucherNumber", id, "error", err)
+		return Amount{}, err
 	}
-	balance := 0.0
+	balance := Amount{}
 	for _, item := range resp.Items {
-		a, err := item.Amount.Float64()
-		if err != nil {
-			return 0, nil
-		}
-		balance += a
+		balance = balance.Add(item.Amount)
 	}
 	return balance, nil
 }
@@ -1,7 +1,6 @@
 package economic
 
 import (
-	"encoding/json"
 	"testing"
 )
 
@@ -10,15 +9,15 @@ func TestCreateJournal(t *testing.T) {
 		EntryTypeNumber:     5,
 		VoucherNumber:       50160,
 		JournalNumber:       6,
-		Date:                "2024-09-26",
-		Amount:              json.Number("500"),
+		Date:                MustParseDate("2024-09-26"),
+		Amount:              MustParseAmount("500"),
 		Currency:            "DKK",
 		AccountNumber:       4610,
 		ContraAccountNumber: 4630,
 		ContraVatCode:       "U25",
 		VatCode:             "U25",
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	err := client.CreateJournalEntry(j)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
@@ -41,15 +40,15 @@ func TestGetBookedCashPaymentById(t *testing.T) {
 		EntryTypeNumber:     5,
 		VoucherNumber:       50160,
 		JournalNumber:       6,
-		Date:                "2024-09-26",
-		Amount:              json.Number("500"),
+		Date:                MustParseDate("2024-09-26"),
+		Amount:              MustParseAmount("500"),
 		Currency:            "DKK",
 		AccountNumber:       4610,
 		ContraAccountNumber: 4630,
 		ContraVatCode:       "U25",
 		VatCode:             "U25",
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	err := client.CreateJournalEntry(j)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
@@ -69,7 +68,23 @@ func TestGetBookedCashPaymentById(t *testing.T) {
 
 func TestCreditBookedCashPayment(t *testing.T) {
 	paymentId := 50160
-	client := getTestClient()
+	client := getTestClient(t)
+	original := &JournalEntry{
+		EntryTypeNumber:     5,
+		VoucherNumber:       paymentId,
+		JournalNumber:       6,
+		Date:                MustParseDate("2024-09-26"),
+		Amount:              MustParseAmount("500"),
+		Currency:            "DKK",
+		AccountNumber:       4610,
+		ContraAccountNumber: 4630,
+		ContraVatCode:       "U25",
+		VatCode:             "U25",
+	}
+	if err := client.CreateJournalEntry(original); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	defer client.DeleteJournalEntry(original)
 	je, err := client.GetCashPaymentById(paymentId)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
@@ -78,8 +93,8 @@ func TestCreditBookedCashPayment(t *testing.T) {
 		EntryTypeNumber:     5,
 		VoucherNumber:       50160,
 		JournalNumber:       6,
-		Date:                "2024-09-26",
-		Amount:              json.Number("-" + je.Amount.String()),
+		Date:                MustParseDate("2024-09-26"),
+		Amount:              je.Amount.Neg(),
 		Currency:            "DKK",
 		AccountNumber:       4610,
 		ContraAccountNumber: 4630,
@@ -91,5 +106,13 @@ func TestCreditBookedCashPayment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
+	defer client.DeleteJournalEntry(j)
+	balance, err := client.GetJournalBalanceById(paymentId)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !balance.IsZero() {
+		t.Fatalf("Expected the credited payment to balance, got %s", balance)
+	}
 
 }
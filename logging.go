@@ -0,0 +1,137 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveLogKeys are attribute keys whose values are never written to the
+// log, whatever the caller passes. Matching is case-insensitive.
+var sensitiveLogKeys = map[string]bool{
+	"agreementgrant":                true,
+	"appsecrettoken":                true,
+	"x-agreementgranttoken":         true,
+	"x-appsecrettoken":              true,
+	"token":                         true,
+	"secret":                        true,
+	"body":                          true,
+	"name":                          true,
+	"email":                         true,
+	"phone":                         true,
+	"mobilephone":                   true,
+	"address":                       true,
+	"corporateidentificationnumber": true,
+	"vatnumber":                     true,
+	"ean":                           true,
+}
+
+// logger returns the logger configured on the client, wrapped so that the
+// client's tokens and personal data are redacted. Without a configured
+// logger nothing is logged.
+func (client *Client) logger() *slog.Logger {
+	if client.Logger == nil {
+		return slog.New(discardHandler{})
+	}
+	var secrets []string
+	for _, s := range []string{client.AgreementGrant, client.AppSecretToken} {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	return slog.New(&redactingHandler{next: client.Logger.Handler(), secrets: secrets})
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// redactingHandler replaces sensitive attributes and scrubs the client's
+// tokens from every message and string value before passing records on.
+type redactingHandler struct {
+	next    slog.Handler
+	secrets []string
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, h.scrub(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = h.redact(a)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(clean), secrets: h.secrets}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), secrets: h.secrets}
+}
+
+func (h *redactingHandler) redact(a slog.Attr) slog.Attr {
+	if sensitiveLogKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		clean := make([]any, len(group))
+		for i, g := range group {
+			clean[i] = h.redact(g)
+		}
+		return slog.Group(a.Key, clean...)
+	case slog.KindString:
+		return slog.String(a.Key, h.scrub(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			if apiErr, ok := AsAPIError(err); ok {
+				// the body may echo personal data back, so only log identifiers
+				return slog.String(a.Key, fmt.Sprintf("e-conomic %d %s (logId %s)", apiErr.StatusCode, apiErr.ErrorCode, apiErr.LogId))
+			}
+			return slog.String(a.Key, h.scrub(errorWithoutQuery(err)))
+		}
+		// arbitrary values (structs, maps) may carry personal data
+		return slog.String(a.Key, redacted)
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// errorWithoutQuery returns the text of err with the query left out of the
+// URL of a failed request, as filters can hold names and emails.
+func errorWithoutQuery(err error) string {
+	msg := err.Error()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		path := logEndpoint(urlErr.URL)
+		msg = strings.ReplaceAll(msg, strconv.Quote(urlErr.URL), strconv.Quote(path))
+		msg = strings.ReplaceAll(msg, urlErr.URL, path)
+	}
+	return msg
+}
+
+func (h *redactingHandler) scrub(s string) string {
+	for _, secret := range h.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package economic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLoggingRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Validation failed","errorCode":"E04300","logId":"log-1","errors":{"email":{"errors":[{"errorMessage":"invalid","inputValue":"abe@example.com"}]}}}`))
+	}))
+	defer server.Close()
+	var buf bytes.Buffer
+	client := &Client{
+		AgreementGrant: "grant-token-value",
+		AppSecretToken: "secret-token-value",
+		RestBaseURL:    server.URL,
+		Logger:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	customer := &Customer{CustomerNumber: 1, Name: "Abe Testesen", Email: "abe@example.com"}
+	client.logger().Info("trying", "token", client.AppSecretToken, "note", "uses grant-token-value", "customer", customer)
+	client.callRestAPI(context.Background(), "customers?filter=email$eq:abe@example.com", http.MethodPost, customer, nil)
+	out := buf.String()
+	for _, secret := range []string{"grant-token-value", "secret-token-value", "abe@example.com", "Abe Testesen"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected %q to be redacted from the log:\n%s", secret, out)
+		}
+	}
+	for _, attr := range []string{"method=POST", "endpoint=customers", "status=400", "attempt=0", "duration=", "logId log-1"} {
+		if !strings.Contains(out, attr) {
+			t.Errorf("Expected %q in the log:\n%s", attr, out)
+		}
+	}
+}
+
+func TestLoggingLeavesOutQueries(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close() // every request fails with a connection error
+	var buf bytes.Buffer
+	client := &Client{
+		AgreementGrant: "grant",
+		AppSecretToken: "secret",
+		RestBaseURL:    server.URL,
+		OpenAPIBaseURL: server.URL,
+		Logger:         slog.New(slog.NewTextHandler(&buf, nil)),
+	}
+	ctx := WithRetryPolicy(context.Background(), RetryNever)
+	client.callRestAPI(ctx, "customers?filter=email$eq:abe@example.com", http.MethodGet, nil, nil)
+	client.callAPI(ctx, "/journalsapi/v1.0.0/draft-entries", http.MethodGet, url.Values{"filter": {"text$eq:Abe Testesen"}}, nil, nil)
+	out := buf.String()
+	for _, personal := range []string{"abe@example.com", "Abe", "filter"} {
+		if strings.Contains(out, personal) {
+			t.Errorf("Expected %q to be left out of the log:\n%s", personal, out)
+		}
+	}
+	for _, path := range []string{"customers", "draft-entries"} {
+		if !strings.Contains(out, path) {
+			t.Errorf("Expected %q in the log:\n%s", path, out)
+		}
+	}
+}
+
+func TestUnconfiguredClientReturnsError(t *testing.T) {
+	client := &Client{}
+	_, err := client.GetCustomerByNumber(1)
+	if !errors.Is(err, ErrClientNotConfigured) {
+		t.Fatalf("Expected ErrClientNotConfigured, got %v", err)
+	}
+	err = client.BookAllEntries(1)
+	if !errors.Is(err, ErrClientNotConfigured) {
+		t.Fatalf("Expected ErrClientNotConfigured, got %v", err)
+	}
+}
@@ -1,65 +1,188 @@
 package economic
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 )
 
 const invoicePageSize = 500
 
 func (client *Client) CreateInvoice(order *Order) (invoice Invoice, err error) {
-	err = client.callRestAPI("invoices/drafts", http.MethodPost, order, &invoice)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-	}
+	return client.CreateInvoiceContext(context.Background(), order)
+}
+
+func (client *Client) CreateInvoiceContext(ctx context.Context, order *Order) (invoice Invoice, err error) {
+	err = client.callRestAPI(ctx, "invoices/drafts", http.MethodPost, order, &invoice, client.draftInvoiceLookup(order, &invoice))
 	return
 }
 
+// draftInvoiceLookup finds the draft an order was turned into, so a failed
+// CreateInvoice can be retried without creating a duplicate. It needs
+// references.other or ExternalId to identify the order; without them the
+// request is not retried after a 5xx or network error.
+func (client *Client) draftInvoiceLookup(order *Order, invoice *Invoice) lookupFunc {
+	ref := ""
+	if order.References != nil {
+		ref = order.References.Other
+	}
+	if ref == "" && order.ExternalId == "" {
+		return nil
+	}
+	return func(ctx context.Context) (bool, error) {
+		tc := &TypedClient[Invoice]{client: client}
+		drafts, err := tc.getEntities(ctx, "invoices/drafts", ListOptions{PageSize: invoicePageSize, Filter: sameOrderFilter(order, ref)})
+		if err != nil {
+			return false, err
+		}
+		found := false
+		for _, draft := range drafts {
+			if order.ExternalId != "" && draft.ExternalId != order.ExternalId {
+				continue
+			}
+			if !found || draft.DraftInvoiceNumber > invoice.DraftInvoiceNumber {
+				*invoice = draft
+				found = true
+			}
+		}
+		return found, nil
+	}
+}
+
+// InvoiceKey is what CreateInvoiceOnce uses to recognize an invoice that was
+// already created for an order.
+type InvoiceKey int
+
+const (
+	ByReference  InvoiceKey = iota // references.other
+	ByExternalId                   // ExternalId
+)
+
+func (client *Client) CreateInvoiceOnce(order *Order, key InvoiceKey) (invoice Invoice, created bool, err error) {
+	return client.CreateInvoiceOnceContext(context.Background(), order, key)
+}
+
+// CreateInvoiceOnceContext creates a draft invoice for order unless a booked
+// or draft invoice for the same customer already has the same key, in which
+// case it returns that one (preferring booked) and created is false. Credit
+// notes may share the reference of the invoice they credit, so only invoices
+// with the same sign as order are considered.
+func (client *Client) CreateInvoiceOnceContext(ctx context.Context, order *Order, key InvoiceKey) (invoice Invoice, created bool, err error) {
+	existing, err := client.findInvoiceForOrder(ctx, order, key)
+	if err != nil || existing != nil {
+		if existing != nil {
+			invoice = *existing
+		}
+		return invoice, false, err
+	}
+	invoice, err = client.CreateInvoiceContext(ctx, order)
+	return invoice, err == nil, err
+}
+
+func (client *Client) findInvoiceForOrder(ctx context.Context, order *Order, key InvoiceKey) (*Invoice, error) {
+	var filter *Filter
+	var match func(Invoice) bool
+	switch key {
+	case ByReference:
+		if order.References == nil || order.References.Other == "" {
+			return nil, fmt.Errorf("CreateInvoiceOnce by reference needs references.other on the order")
+		}
+		filter = sameOrderFilter(order, order.References.Other)
+		match = func(Invoice) bool { return true }
+	case ByExternalId:
+		if order.ExternalId == "" {
+			return nil, fmt.Errorf("CreateInvoiceOnce by external id needs ExternalId on the order")
+		}
+		// ExternalId is compared here; the customer narrows down the candidates.
+		filter = sameOrderFilter(order, "")
+		match = func(invoice Invoice) bool { return invoice.ExternalId == order.ExternalId }
+	default:
+		return nil, fmt.Errorf("unknown invoice key %d", key)
+	}
+	credit := orderNetAmount(order).Sign() < 0
+	tc := &TypedClient[Invoice]{client: client}
+	for _, class := range []string{"booked", "drafts"} {
+		invoices, err := tc.getEntities(ctx, "invoices/"+class, ListOptions{PageSize: invoicePageSize, Filter: filter})
+		if err != nil {
+			return nil, err
+		}
+		for _, invoice := range invoices {
+			if match(invoice) && (invoice.NetAmount.Sign() < 0) == credit {
+				return &invoice, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func orderNetAmount(order *Order) Amount {
+	net := Amount{}
+	for _, line := range order.Lines {
+		net = net.Add(lineNetAmount(line, order.Currency))
+	}
+	return net
+}
+
+// sameOrderFilter matches documents with the customer and reference of
+// order. The date is left out so a document is found even when the order is
+// created again with another date.
+func sameOrderFilter(order *Order, ref string) *Filter {
+	filter := &Filter{}
+	filter.AndCondition("customer.customerNumber", FilterOperatorEquals, order.Customer.CustomerNumber)
+	if ref != "" {
+		filter.AndCondition("references.other", FilterOperatorEquals, ref)
+	}
+	return filter
+}
+
 func (client *Client) GetPaidInvoices(date string) ([]Invoice, error) {
-	if !ValidateDate(date) {
-		return nil, fmt.Errorf("Please use the YYYY-MM-DD date format")
+	return client.GetPaidInvoicesContext(context.Background(), date)
+}
+
+func (client *Client) GetPaidInvoicesContext(ctx context.Context, date string) ([]Invoice, error) {
+	after, err := ParseDate(date)
+	if err != nil {
+		return nil, err
 	}
 	filter := &Filter{}
-	date = url.QueryEscape(date)
-	filter.AndCondition("date", FilterOperatorGreaterThan, date)
+	filter.AndCondition("date", FilterOperatorGreaterThan, after)
 	baseUrl := "invoices/paid"
 	tc := &TypedClient[Invoice]{client: client}
-	return tc.getEntities(baseUrl, invoicePageSize, filter.filterStr)
+	return tc.getEntities(ctx, baseUrl, ListOptions{PageSize: invoicePageSize, Filter: filter})
 }
 
 // Deletes a draft invoice, i.e. not booked. A 404 response is treated as
 // success since the goal (draft no longer exists) is already achieved.
 func (client *Client) DeleteInvoice(invoiceNo int) error {
-	err := client.callRestAPI(fmt.Sprintf("invoices/drafts/%d", invoiceNo), http.MethodDelete, nil, nil)
-	var apiErr *APIError
-	if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
-		log.Printf("DeleteInvoice: draft %d already gone (404), treating as success", invoiceNo)
+	return client.DeleteInvoiceContext(context.Background(), invoiceNo)
+}
+
+func (client *Client) DeleteInvoiceContext(ctx context.Context, invoiceNo int) error {
+	err := client.callRestAPI(ctx, fmt.Sprintf("invoices/drafts/%d", invoiceNo), http.MethodDelete, nil, nil)
+	if IsNotFound(err) {
+		client.logger().Info("DeleteInvoice: draft already gone (404), treating as success", "draftInvoiceNumber", invoiceNo)
 		return nil
 	}
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-	}
 	return err
 }
 
 func (client *Client) GetDraftInvoice(invoiceNo int) (invoice Invoice, err error) {
-	err = client.callRestAPI(fmt.Sprintf("invoices/drafts/%d", invoiceNo), http.MethodGet, nil, &invoice)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-	}
+	return client.GetDraftInvoiceContext(context.Background(), invoiceNo)
+}
+
+func (client *Client) GetDraftInvoiceContext(ctx context.Context, invoiceNo int) (invoice Invoice, err error) {
+	err = client.callRestAPI(ctx, fmt.Sprintf("invoices/drafts/%d", invoiceNo), http.MethodGet, nil, &invoice)
 	return
 }
 
 func (client *Client) GetBookedInvoice(invoiceNo int) (invoice Invoice, err error) {
-	err = client.callRestAPI(fmt.Sprintf("invoices/booked/%d", invoiceNo), http.MethodGet, nil, &invoice)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-	}
+	return client.GetBookedInvoiceContext(context.Background(), invoiceNo)
+}
+
+func (client *Client) GetBookedInvoiceContext(ctx context.Context, invoiceNo int) (invoice Invoice, err error) {
+	err = client.callRestAPI(ctx, fmt.Sprintf("invoices/booked/%d", invoiceNo), http.MethodGet, nil, &invoice)
 	return
 }
 
@@ -82,45 +205,73 @@ func ValidateInvoiceClass(class string) error {
 }
 
 func (client *Client) GetInvoicesByClassAndRef(class, ref string) ([]Invoice, error) {
-	return client.getInvoicesForClass(class, "references.other", ref)
+	return client.GetInvoicesByClassAndRefContext(context.Background(), class, ref)
+}
+
+func (client *Client) GetInvoicesByClassAndRefContext(ctx context.Context, class, ref string) ([]Invoice, error) {
+	return client.getInvoicesForClass(ctx, class, "references.other", ref)
 }
 
 // Fails if no unique match is found on 'other references'
 func (client *Client) GetOneInvoiceByClassAndRef(class, ref string) (Invoice, error) {
-	invoices, err := client.GetInvoicesByClassAndRef(class, ref)
+	return client.GetOneInvoiceByClassAndRefContext(context.Background(), class, ref)
+}
+
+func (client *Client) GetOneInvoiceByClassAndRefContext(ctx context.Context, class, ref string) (Invoice, error) {
+	invoices, err := client.GetInvoicesByClassAndRefContext(ctx, class, ref)
 	if err != nil {
 		return Invoice{}, err
 	}
 	if len(invoices) != 1 {
-		log.Printf("ERROR invalid number of invoices %#v", invoices)
+		client.logger().Error("invalid number of invoices for ref", "class", class, "count", len(invoices))
 		return Invoice{}, fmt.Errorf("unable to make unique match with ref %s", ref)
 	}
 	return invoices[0], nil
 }
 
 func (client *Client) GetDraftInvoiceByRef(ref string) (invoice Invoice, err error) {
-	return client.GetOneInvoiceByClassAndRef("drafts", ref)
+	return client.GetDraftInvoiceByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetDraftInvoiceByRefContext(ctx context.Context, ref string) (invoice Invoice, err error) {
+	return client.GetOneInvoiceByClassAndRefContext(ctx, "drafts", ref)
 }
 
 func (client *Client) GetBookedInvoiceByRef(ref string) (invoice Invoice, err error) {
-	return client.GetOneInvoiceByClassAndRef("booked", ref)
+	return client.GetBookedInvoiceByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetBookedInvoiceByRefContext(ctx context.Context, ref string) (invoice Invoice, err error) {
+	return client.GetOneInvoiceByClassAndRefContext(ctx, "booked", ref)
 }
 
 func (client *Client) GetDraftInvoicesByRef(ref string) (invoices []Invoice, err error) {
-	return client.GetInvoicesByClassAndRef("drafts", ref)
+	return client.GetDraftInvoicesByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetDraftInvoicesByRefContext(ctx context.Context, ref string) (invoices []Invoice, err error) {
+	return client.GetInvoicesByClassAndRefContext(ctx, "drafts", ref)
 }
 
 func (client *Client) GetBookedInvoicesByRef(ref string) (invoices []Invoice, err error) {
-	return client.GetInvoicesByClassAndRef("booked", ref)
+	return client.GetBookedInvoicesByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetBookedInvoicesByRefContext(ctx context.Context, ref string) (invoices []Invoice, err error) {
+	return client.GetInvoicesByClassAndRefContext(ctx, "booked", ref)
 }
 
 func (client *Client) GetInvoicesByRef(ref string) ([]Invoice, error) {
-	draftInvoices, draftErr := client.GetDraftInvoicesByRef(ref)
+	return client.GetInvoicesByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetInvoicesByRefContext(ctx context.Context, ref string) ([]Invoice, error) {
+	draftInvoices, draftErr := client.GetDraftInvoicesByRefContext(ctx, ref)
 	if draftErr != nil {
 		return draftInvoices, draftErr
 	}
 
-	bookedInvoices, bookedErr := client.GetBookedInvoicesByRef(ref)
+	bookedInvoices, bookedErr := client.GetBookedInvoicesByRefContext(ctx, ref)
 	if bookedErr != nil {
 		return bookedInvoices, bookedErr
 	}
@@ -132,50 +283,109 @@ func (client *Client) GetInvoicesByRef(ref string) ([]Invoice, error) {
 // if the returned invoice has a booked invoice number not equal to zero, it is booked
 // if the returned invoice has a draft invoice number not equal to zero, it is a draft
 func (client *Client) GetInvoiceByRef(ref string) (invoice Invoice, err error) {
-	invoice, err = client.GetDraftInvoiceByRef(ref)
+	return client.GetInvoiceByRefContext(context.Background(), ref)
+}
+
+func (client *Client) GetInvoiceByRefContext(ctx context.Context, ref string) (invoice Invoice, err error) {
+	invoice, err = client.GetDraftInvoiceByRefContext(ctx, ref)
 	if err == nil {
 		return
 	}
-	invoice, err = client.GetBookedInvoiceByRef(ref)
+	invoice, err = client.GetBookedInvoiceByRefContext(ctx, ref)
 	if err == nil {
 		return
 	}
-	log.Printf("ERROR: %#v", err)
+	client.logger().Error("unable to find invoice by ref", "error", err)
 	err = fmt.Errorf("unable to find invoice with ref %s", ref)
 	return
 }
 
+// SendBy is how e-conomic sends an invoice to the customer when it is booked.
+// The zero value, like SendByNone, books without sending.
+type SendBy string
+
+const (
+	SendByNone  SendBy = "none"  // book without sending
+	SendByEmail SendBy = "Email" // email the invoice to the customer's invoice address
+	SendByEAN   SendBy = "ean"   // send an e-invoice to the customer's EAN (NemHandel)
+)
+
+// sendBy is the sendBy property of a booking; empty when nothing is sent.
+func (s SendBy) sendBy() (string, error) {
+	switch s {
+	case "", SendByNone:
+		return "", nil
+	case SendByEmail, SendByEAN:
+		return string(s), nil
+	}
+	return "", fmt.Errorf("unknown SendBy %q", string(s))
+}
+
 type BookInvoiceOptions struct {
-	SendBy         string `json:"sendBy,omitempty"`
-	BookWithNumber *int   `json:"bookWithNumber,omitempty"`
+	SendBy         SendBy
+	BookWithNumber *int
 }
 
 func (client *Client) BookInvoice(invoiceNo int, options ...BookInvoiceOptions) (invoice Invoice, err error) {
+	return client.BookInvoiceContext(context.Background(), invoiceNo, options...)
+}
+
+// BookInvoiceContext books a draft invoice. The invoice is only sent to the
+// customer if options ask for it with SendByEmail or SendByEAN. Earlier
+// versions emailed it by default; pass SendByEmail to keep doing so.
+func (client *Client) BookInvoiceContext(ctx context.Context, invoiceNo int, options ...BookInvoiceOptions) (invoice Invoice, err error) {
 	type bookBody struct {
 		DraftInvoice struct {
 			DraftInvoiceNumber int `json:"draftInvoiceNumber"`
 		} `json:"draftInvoice"`
-		BookInvoiceOptions
+		SendBy         string `json:"sendBy,omitempty"`
+		BookWithNumber *int   `json:"bookWithNumber,omitempty"`
 	}
 	body := bookBody{}
 	body.DraftInvoice.DraftInvoiceNumber = invoiceNo
-	body.SendBy = "Email"
 	if len(options) > 0 {
-		body.BookInvoiceOptions = options[0]
-	}
-	err = client.callRestAPI("invoices/booked", http.MethodPost, body, &invoice)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
+		if body.SendBy, err = options[0].SendBy.sendBy(); err != nil {
+			return
+		}
+		body.BookWithNumber = options[0].BookWithNumber
 	}
+	err = client.callRestAPI(ctx, "invoices/booked", http.MethodPost, body, &invoice, client.bookedInvoiceLookup(invoiceNo, body.BookWithNumber, &invoice))
 	return
 }
 
+// bookedInvoiceLookup checks whether a draft was booked by a failed
+// BookInvoice. While the draft exists it was not, and booking is retried. Once
+// it is gone the booked invoice can only be found by its number.
+func (client *Client) bookedInvoiceLookup(draftInvoiceNo int, bookWithNumber *int, invoice *Invoice) lookupFunc {
+	return func(ctx context.Context) (bool, error) {
+		_, err := client.GetDraftInvoiceContext(ctx, draftInvoiceNo)
+		if err == nil {
+			return false, nil
+		}
+		if !IsNotFound(err) {
+			return false, err
+		}
+		if bookWithNumber == nil {
+			return false, fmt.Errorf("draft invoice %d is gone and was probably booked", draftInvoiceNo)
+		}
+		*invoice, err = client.GetBookedInvoiceContext(ctx, *bookWithNumber)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
 type TypedClient[T any] struct {
 	client     *Client
 	entityType T
 }
 
 func (client *Client) GetInvoices(windows ...TimeWindow) ([]Invoice, error) {
+	return client.GetInvoicesContext(context.Background(), windows...)
+}
+
+func (client *Client) GetInvoicesContext(ctx context.Context, windows ...TimeWindow) ([]Invoice, error) {
 	window := YesterdayWindow()
 	if len(windows) > 0 {
 		window = windows[0]
@@ -185,69 +395,101 @@ func (client *Client) GetInvoices(windows ...TimeWindow) ([]Invoice, error) {
 	filter.AndCondition("date", FilterOperatorLessThanOrEqual, window.To.Format("2006-01-02"))
 
 	tc := &TypedClient[Invoice]{client: client}
-	draft, err := tc.getEntities("invoices/drafts", invoicePageSize, filter.filterStr)
+	draft, err := tc.getEntities(ctx, "invoices/drafts", ListOptions{PageSize: invoicePageSize, Filter: filter})
 	if err != nil {
 		return nil, err
 	}
 	if len(draft) > 0 {
-		log.Printf("GetInvoices: %d draft invoices found", len(draft))
+		client.logger().Debug("GetInvoices: draft invoices found", "count", len(draft))
 	}
-	booked, err := tc.getEntities("invoices/booked", invoicePageSize, filter.filterStr)
+	booked, err := tc.getEntities(ctx, "invoices/booked", ListOptions{PageSize: invoicePageSize, Filter: filter})
 	if err != nil {
 		return nil, err
 	}
 	if len(booked) > 0 {
-		log.Printf("GetInvoices: %d booked invoices found", len(booked))
+		client.logger().Debug("GetInvoices: booked invoices found", "count", len(booked))
 	}
 	return append(draft, booked...), nil
 }
 
 func (client *Client) GetBookedInvoices(pagesize int) (invoices []Invoice, err error) {
+	return client.GetBookedInvoicesContext(context.Background(), pagesize)
+}
+
+func (client *Client) GetBookedInvoicesContext(ctx context.Context, pagesize int) (invoices []Invoice, err error) {
 	baseUrl := "invoices/booked"
 	tc := &TypedClient[Invoice]{client: client}
-	return tc.getEntities(baseUrl, pagesize, "")
+	return tc.getEntities(ctx, baseUrl, ListOptions{PageSize: pagesize})
 }
 
 func (client *Client) GetDraftInvoices(pagesize int) (invoices []Invoice, err error) {
+	return client.GetDraftInvoicesContext(context.Background(), pagesize)
+}
+
+func (client *Client) GetDraftInvoicesContext(ctx context.Context, pagesize int) (invoices []Invoice, err error) {
 	baseUrl := "invoices/drafts"
 	tc := &TypedClient[Invoice]{client: client}
-	return tc.getEntities(baseUrl, pagesize, "")
+	return tc.getEntities(ctx, baseUrl, ListOptions{PageSize: pagesize})
 }
 
 type CreditNoteOptions struct {
-	Date    string // YYYY-MM-DD; defaults to today if empty
-	DueDate string // YYYY-MM-DD; defaults to Date if empty
+	Date    Date // Defaults to today if zero.
+	DueDate Date // Defaults to Date if zero.
+	// Lines selects what to credit; empty credits every line in full.
+	Lines []CreditLine
+	// Note explains the credit note. It replaces the first text line of the
+	// original invoice's notes.
+	Note string
+	// AllowOverCredit skips the check that the credit note, with the credit
+	// notes already made for the invoice, does not credit more than the
+	// invoice's lines.
+	AllowOverCredit bool
 }
 
 // Creates a draft credit note based on a booked invoice, identified by its booked invoice number.
 // The credit note will have negative amounts and, like any other draft, still needs to be booked
-// (e.g. via BookInvoice) to become final.
+// (e.g. via BookInvoice) to become final. Credit notes get the invoice's references.other with its
+// booked invoice number (see CreditNoteRef), which is how earlier credit notes for the invoice are
+// found to guard against crediting too much.
 func (client *Client) CreateCreditNoteForBookedInvoice(invoiceNo int, options ...CreditNoteOptions) (creditNote Invoice, err error) {
-	invoiceToCredit, err := client.GetBookedInvoice(invoiceNo)
+	return client.CreateCreditNoteForBookedInvoiceContext(context.Background(), invoiceNo, options...)
+}
+
+func (client *Client) CreateCreditNoteForBookedInvoiceContext(ctx context.Context, invoiceNo int, options ...CreditNoteOptions) (creditNote Invoice, err error) {
+	invoiceToCredit, err := client.GetBookedInvoiceContext(ctx, invoiceNo)
 	if err != nil {
-		log.Printf("ERROR: %#v", err)
 		return
 	}
+	if invoiceToCredit.Customer == nil || invoiceToCredit.Recipient == nil {
+		err = fmt.Errorf("booked invoice %d has no customer or recipient to credit", invoiceNo)
+		return
+	}
+	var opts CreditNoteOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
 	// copy the original invoice's lines, negating the unit price so the
 	// credit note mirrors it itemized rather than as a single lump sum
-	lines := make([]OrderLine, len(invoiceToCredit.Lines))
-	for i, line := range invoiceToCredit.Lines {
-		lines[i] = line
-		lines[i].UnitNetPrice = -line.UnitNetPrice
+	lines, err := creditLines(invoiceToCredit, opts.Lines)
+	if err != nil {
+		return
+	}
+	ref := CreditNoteRef(invoiceToCredit)
+	if !opts.AllowOverCredit {
+		if err = client.checkCreditLimit(ctx, invoiceToCredit, ref, lines); err != nil {
+			return
+		}
 	}
 	exchangeRate := invoiceToCredit.ExchangeRate
 	// a credit note is due immediately by default, not on whatever schedule the
 	// original invoice's payment terms implied - mirrors createInvoice()'s isCreditNote handling
-	date := time.Now().Format("2006-01-02")
+	date := Today(nil)
+	if !opts.Date.IsZero() {
+		date = opts.Date
+	}
 	dueDate := date
-	if len(options) > 0 {
-		if options[0].Date != "" {
-			date = options[0].Date
-			dueDate = date
-		}
-		if options[0].DueDate != "" {
-			dueDate = options[0].DueDate
-		}
+	if !opts.DueDate.IsZero() {
+		dueDate = opts.DueDate
 	}
 	order := &Order{
 		Date:         date,
@@ -268,21 +510,24 @@ func (client *Client) CreateCreditNoteForBookedInvoice(invoiceNo int, options ..
 		Notes:      invoiceToCredit.Notes,
 		Lines:      lines,
 		ExternalId: invoiceToCredit.ExternalId,
+		References: &References{Other: ref},
 	}
-	if invoiceToCredit.References != nil {
-		order.References = &References{Other: invoiceToCredit.References.Other}
-		if invoiceToCredit.References.CustomerContact != nil {
-			customerContact := *invoiceToCredit.References.CustomerContact
-			order.References.CustomerContact = &customerContact
+	if opts.Note != "" {
+		notes := Notes{}
+		if invoiceToCredit.Notes != nil {
+			notes = *invoiceToCredit.Notes
 		}
+		notes.TextLine1 = opts.Note
+		order.Notes = &notes
+	}
+	if invoiceToCredit.References != nil && invoiceToCredit.References.CustomerContact != nil {
+		customerContact := *invoiceToCredit.References.CustomerContact
+		order.References.CustomerContact = &customerContact
 	}
 	if invoiceToCredit.ProjectNumber > 0 {
 		order.Project = &Project{ProjectNumber: invoiceToCredit.ProjectNumber}
 	}
-	creditNote, err = client.CreateInvoice(order)
-	if err != nil {
-		log.Printf("ERROR: %#v", err)
-	}
+	creditNote, err = client.CreateInvoiceContext(ctx, order)
 	return
 }
 
@@ -304,16 +549,17 @@ func (o Order) ID() int {
 
 // required: date, currency, layout, paymentTerms, customer, recipient, recipient.name, recipient.vatZone
 type Order struct {
-	Date                 string            `json:"date"`                           //Order issue date. Format according to ISO-8601 (YYYY-MM-DD)."`
+	OrderNumber          int               `json:"orderNumber,omitempty"`          //A reference number for the order document. Only set on sales orders; e-conomic assigns it."`
+	Date                 Date              `json:"date"`                           //Order issue date. Format according to ISO-8601 (YYYY-MM-DD)."`
 	Currency             string            `json:"currency"`                       //The ISO 4217 3-letter currency code of the order."`
 	ExchangeRate         *float64          `json:"exchangeRate,omitempty"`         //The desired exchange rate between the order currency and the base currency of the agreement. The exchange rate expresses how much it will cost in base currency to buy 100 units of the order currency. If no exchange rate is supplied, the system will get the current daily rate, unless the order currency is the same as the base currency, in which case it will be set to 100."`
-	DueDate              *string           `json:"dueDate,omitempty"`              //The date the order is due for payment. This property is only used if the terms of payment is of type 'duedate', in which case it is a mandatory property. Format according to ISO-8601 (YYYY-MM-DD)."`
-	GrossAmount          *float64          `json:"grossAmount,omitempty"`          //The total order amount in the order currency after all taxes and discounts have been applied."`
-	MarginInBaseCurrency *float64          `json:"marginInBaseCurrency,omitempty"` //The difference between the cost price of the items on the order and the sales net order amount in base currency."`
+	DueDate              *Date             `json:"dueDate,omitempty"`              //The date the order is due for payment. This property is only used if the terms of payment is of type 'duedate', in which case it is a mandatory property. Format according to ISO-8601 (YYYY-MM-DD)."`
+	GrossAmount          *Amount           `json:"grossAmount,omitempty"`          //The total order amount in the order currency after all taxes and discounts have been applied."`
+	MarginInBaseCurrency *Amount           `json:"marginInBaseCurrency,omitempty"` //The difference between the cost price of the items on the order and the sales net order amount in base currency."`
 	MarginPercentage     *float64          `json:"marginPercentage,omitempty"`     //The margin expressed as a percentage. If the net order amount is less than the cost price this number will be negative."`
-	NetAmount            float64           `json:"netAmount,omitempty"`            //The total order amount in the order currency before all taxes and discounts have been applied."`
-	RoundingAmount       float64           `json:"roundingAmount,omitempty"`       //The total rounding error, if any, on the order in base currency."`
-	VatAmount            float64           `json:"vatAmount,omitempty"`            //The total amount of VAT on the order in the order currency. This will have the same sign as net amount"`
+	NetAmount            *Amount           `json:"netAmount,omitempty"`            //The total order amount in the order currency before all taxes and discounts have been applied."`
+	RoundingAmount       *Amount           `json:"roundingAmount,omitempty"`       //The total rounding error, if any, on the order in base currency."`
+	VatAmount            *Amount           `json:"vatAmount,omitempty"`            //The total amount of VAT on the order in the order currency. This will have the same sign as net amount"`
 	Layout               Layout            `json:"layout"`                         //The layout used by the order."`
 	Project              *Project          `json:"project,omitempty"`              //The project the order is connected to."`
 	PaymentTerms         PaymentTerms      `json:"paymentTerms"`                   //The terms of payment for the order."`
@@ -326,6 +572,7 @@ type Order struct {
 	Pdf                  *Pdf              `json:"pdf,omitempty"`                  //References a pdf representation of this order."`
 	Lines                []OrderLine       `json:"lines"`                          //An array containing the specific order lines."`
 	ExternalId           string            `json:"externalID,omitempty"`
+	Self                 string            `json:"self,omitempty"` //The unique self reference of the sales order."`
 	Soap                 *struct {
 		OrderHandle struct {
 			ID int `json:"id"`
@@ -373,7 +620,7 @@ type Delivery struct {
 	City          string `json:"city,omitempty"`          //The city of the place of delivery"`
 	Country       string `json:"country,omitempty"`       //The country of the place of delivery"`
 	DeliveryTerms string `json:"deliveryTerms,omitempty"` //Details about the terms of delivery."`
-	DeliveryDate  string `json:"deliveryDate,omitempty"`  //The date of delivery."`
+	DeliveryDate  *Date  `json:"deliveryDate,omitempty"`  //The date of delivery."`
 }
 
 type Notes struct {
@@ -407,29 +654,25 @@ type OrderLine struct {
 	Unit                     *Unit                     `json:"unit,omitempty"`                     //The unit of measure applied to the order line."`
 	Product                  *Product                  `json:"product,omitempty"`                  //The product or service offered on the order line."`
 	Quantity                 float64                   `json:"quantity,omitempty"`                 //The number of units of goods on the order line."`
-	UnitNetPrice             float64                   `json:"unitNetPrice,omitempty"`             //The price of 1 unit of the goods or services on the order line in the order currency."`
+	UnitNetPrice             *Amount                   `json:"unitNetPrice,omitempty"`             //The price of 1 unit of the goods or services on the order line in the order currency."`
 	DiscountPercentage       float64                   `json:"discountPercentage,omitempty"`       //A line discount expressed as a percentage."`
-	UnitCostPrice            float64                   `json:"unitCostPrice,omitempty"`            //The cost price of 1 unit of the goods or services in the order currency."`
-	MarginInBaseCurrency     float64                   `json:"marginInBaseCurrency,omitempty"`     //The difference between the net price and the cost price on the order line in base currency."`
+	UnitCostPrice            *Amount                   `json:"unitCostPrice,omitempty"`            //The cost price of 1 unit of the goods or services in the order currency."`
+	MarginInBaseCurrency     *Amount                   `json:"marginInBaseCurrency,omitempty"`     //The difference between the net price and the cost price on the order line in base currency."`
 	MarginPercentage         float64                   `json:"marginPercentage,omitempty"`         //The margin on the order line expressed as a percentage."`
 	DepartmentalDistribution *DepartmentalDistribution `json:"departmentalDistribution,omitempty"` //A departmental distribution defines which departments this entry is distributed between. This requires the departments module to be enabled."`
 }
 
 type Accrual struct {
-	StartDate string `json:"startDate,omitempty"` //The start date for the accrual. Format: YYYY-MM-DD."`
-	EndDate   string `json:"endDate,omitempty"`   //The end date for the accrual. Format: YYYY-MM-DD."`
+	StartDate *Date `json:"startDate,omitempty"` //The start date for the accrual. Format: YYYY-MM-DD."`
+	EndDate   *Date `json:"endDate,omitempty"`   //The end date for the accrual. Format: YYYY-MM-DD."`
 }
 
 type Unit struct {
 	UnitNumber int    `json:"unitNumber"`     //The unique identifier of the unit."`
+	Name       string `json:"name,omitempty"` //The name of the unit (e.g. 'kg' for weight or 'l' for volume)."`
 	Self       string `json:"self,omitempty"` //A unique reference to the unit resource."`
 }
 
-type Product struct {
-	ProductNumber string `json:"productNumber,omitempty"` //The unique product number. This can be a stock keeping unit identifier (SKU)."`
-	Self          string `json:"self,omitempty"`          //A unique reference to the product resource."`
-}
-
 type DepartmentalDistribution struct {
 	DepartmentalDistributionNumber int    `json:"departmentalDistributionNumber"` //A unique identifier of the departmental distribution."`
 	DistributionType               string `json:"distributionType,omitempty"`     //Type of the distribution"`
@@ -437,18 +680,30 @@ type DepartmentalDistribution struct {
 }
 
 func (client *Client) ClassifyInvoiceByRef(ref string) ([]string, error) {
-	return client.classifyInvoiceByTypeAndNumber("ref", 0, ref)
+	return client.ClassifyInvoiceByRefContext(context.Background(), ref)
+}
+
+func (client *Client) ClassifyInvoiceByRefContext(ctx context.Context, ref string) ([]string, error) {
+	return client.classifyInvoiceByTypeAndNumber(ctx, "ref", 0, ref)
 }
 
 func (client *Client) ClassifyInvoiceByBookedInvoiceNo(bookedInvoiceNumber int) ([]string, error) {
-	return client.classifyInvoiceByTypeAndNumber("bookedInvoice", bookedInvoiceNumber, "")
+	return client.ClassifyInvoiceByBookedInvoiceNoContext(context.Background(), bookedInvoiceNumber)
+}
+
+func (client *Client) ClassifyInvoiceByBookedInvoiceNoContext(ctx context.Context, bookedInvoiceNumber int) ([]string, error) {
+	return client.classifyInvoiceByTypeAndNumber(ctx, "bookedInvoice", bookedInvoiceNumber, "")
 }
 
 func (client *Client) ClassifyInvoiceByDraftInvoiceNo(draftInvoiceNumber int) ([]string, error) {
-	return client.classifyInvoiceByTypeAndNumber("draftInvoice", draftInvoiceNumber, "")
+	return client.ClassifyInvoiceByDraftInvoiceNoContext(context.Background(), draftInvoiceNumber)
+}
+
+func (client *Client) ClassifyInvoiceByDraftInvoiceNoContext(ctx context.Context, draftInvoiceNumber int) ([]string, error) {
+	return client.classifyInvoiceByTypeAndNumber(ctx, "draftInvoice", draftInvoiceNumber, "")
 }
 
-func (client *Client) classifyInvoiceByTypeAndNumber(invoiceType string, number int, ref string) ([]string, error) {
+func (client *Client) classifyInvoiceByTypeAndNumber(ctx context.Context, invoiceType string, number int, ref string) ([]string, error) {
 	var filterType, filterValue string
 	var possibleClasses []string
 	switch invoiceType {
@@ -466,10 +721,9 @@ func (client *Client) classifyInvoiceByTypeAndNumber(invoiceType string, number
 		filterValue = ref
 		possibleClasses = invoiceClasses[1:]
 	}
-	filterValue = url.QueryEscape(filterValue)
 	classes := []string{}
 	for _, class := range possibleClasses {
-		invoices, err := client.getInvoicesForClass(class, filterType, filterValue)
+		invoices, err := client.getInvoicesForClass(ctx, class, filterType, filterValue)
 		if err != nil {
 			return nil, err
 		}
@@ -481,7 +735,7 @@ func (client *Client) classifyInvoiceByTypeAndNumber(invoiceType string, number
 	return classes, nil
 }
 
-func (client *Client) getInvoicesForClass(class string, filterType string, filterValue string) ([]Invoice, error) {
+func (client *Client) getInvoicesForClass(ctx context.Context, class string, filterType string, filterValue string) ([]Invoice, error) {
 	var invoices []Invoice
 	err := ValidateInvoiceClass(class)
 	if err != nil {
@@ -490,9 +744,8 @@ func (client *Client) getInvoicesForClass(class string, filterType string, filte
 	filter := &Filter{}
 	filter.AndCondition(filterType, FilterOperatorEquals, filterValue)
 	results := CollectionReponse[Invoice]{}
-	err = client.callRestAPI(fmt.Sprintf("invoices/"+class+"?filter="+filter.filterStr), http.MethodGet, nil, &results)
+	err = client.callRestAPI(ctx, "invoices/"+class+"?filter="+url.QueryEscape(filter.String()), http.MethodGet, nil, &results)
 	if err != nil {
-		log.Printf("ERROR: %#v", err)
 		return invoices, err
 	}
 
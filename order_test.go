@@ -1,7 +1,7 @@
 package economic
 
 import (
-	"net/http"
+	"bytes"
 	"testing"
 )
 
@@ -28,14 +28,14 @@ func TestCreateOrderDraft(t *testing.T) {
 		Name:  "Abe Testesen",
 		Email: "jungle@abe.com",
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	_, err := client.GetOrCreateCustomer(c, &contact, 1)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
 	defer client.DeleteCustomer(c)
 	order := &Order{
-		Date:     "2023-10-01",
+		Date:     MustParseDate("2023-10-01"),
 		Currency: "DKK",
 		Layout: Layout{
 			LayoutNumber: 19,
@@ -60,33 +60,33 @@ func TestCreateOrderDraft(t *testing.T) {
 }
 
 func TestGetLayouts(t *testing.T) {
-	resp := map[string]any{}
-	client := getTestClient()
-	err := client.callRestAPI("layouts", http.MethodGet, nil, &resp)
+	client := getTestClient(t)
+	layouts, err := client.GetLayouts()
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	t.Fatalf("got %+v", resp)
+	if len(layouts) == 0 || layouts[0].LayoutNumber == 0 {
+		t.Fatalf("Expected layouts, got %+v", layouts)
+	}
+	t.Logf("got %+v", layouts)
 }
 
 func TestGetDrafts(t *testing.T) {
-	resp := map[string]any{}
-	client := getTestClient()
-	err := client.callRestAPI("orders/drafts", http.MethodGet, nil, &resp)
+	client := getTestClient(t)
+	orders, err := client.GetOrders("drafts")
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	t.Fatalf("got %+v", resp)
+	t.Logf("got %+v", orders)
 }
 
 func TestGetProducts(t *testing.T) {
-	resp := map[string]any{}
-	client := getTestClient()
-	err := client.callRestAPI("products", http.MethodGet, nil, &resp)
+	client := getTestClient(t)
+	products, err := client.GetProducts()
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	t.Fatalf("got %+v", resp)
+	t.Logf("got %+v", products)
 }
 
 func TestGetInvoice(t *testing.T) {
@@ -112,14 +112,14 @@ func TestGetInvoice(t *testing.T) {
 		Name:  "Abe Testesen",
 		Email: "jungle@abe.com",
 	}
-	client := getTestClient()
+	client := getTestClient(t)
 	_, err := client.GetOrCreateCustomer(c, &contact, 1)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
 	ref := "Test1234567" // Must be unique
 	order := &Order{
-		Date:     "2023-10-01",
+		Date:     MustParseDate("2023-10-01"),
 		Currency: "DKK",
 		References: &References{
 			Other: ref,
@@ -143,7 +143,7 @@ func TestGetInvoice(t *testing.T) {
 				LineNumber:   1,
 				Product:      &Product{ProductNumber: "1"},
 				Quantity:     1,
-				UnitNetPrice: 300,
+				UnitNetPrice: MustParseAmount("300").Ptr(),
 			},
 		}}
 	invoice, err := client.CreateInvoice(order)
@@ -171,11 +171,29 @@ func TestGetInvoice(t *testing.T) {
 }
 
 func TestGetBooked(t *testing.T) {
-	client := getTestClient()
+	client := getTestClient(t)
 	invoices, err := client.GetBookedInvoices(20)
 	if err != nil {
 		t.Fatalf("Error: %s", err)
 	}
-	t.Fatalf("got %+v", invoices)
+	t.Logf("got %+v", invoices)
+
+}
 
+func TestDownloadOrderPdf(t *testing.T) {
+	client := getTestClient(t)
+	order, err := client.CreateOrder(testOrder("order-pdf"))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	var pdf bytes.Buffer
+	if err := client.DownloadOrderPdf("drafts", order.OrderNumber, &pdf); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF")) {
+		t.Fatalf("Expected a PDF, got %q", pdf.String())
+	}
+	if err := client.DownloadOrderPdf("booked", order.OrderNumber, &pdf); err == nil {
+		t.Fatalf("Expected an error for an unknown class")
+	}
 }
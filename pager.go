@@ -0,0 +1,267 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNoMorePages is returned by Pager.Next after the last page.
+var ErrNoMorePages = errors.New("no more pages")
+
+// Pager walks a paginated collection one page at a time, so large
+// collections can be processed without loading them into memory.
+//
+//	pager := client.InvoicesPager("booked", ListOptions{PageSize: 500})
+//	for pager.More() {
+//		invoices, err := pager.Next(ctx)
+//		...
+//		save(pager.Position()) // to resume later with Seek
+//	}
+type Pager[T any] struct {
+	fetch    func(ctx context.Context, position string) (page []T, next string, err error)
+	position string
+	done     bool
+
+	// pending are the items of the last page that ForEach stopped before,
+	// fetched from pendingPosition.
+	pending         []T
+	pendingPosition string
+}
+
+// More reports whether there are pages, or items of a page, left.
+func (p *Pager[T]) More() bool {
+	return !p.done || len(p.pending) > 0
+}
+
+// Position returns where the next page starts: a link for the REST
+// collections and a cursor for the OpenAPI endpoints. The empty position is
+// the first page. After ForEach stopped partway through a page it is the
+// position of that page, so resuming from it repeats the items before the
+// stop rather than skipping the ones after it.
+func (p *Pager[T]) Position() string {
+	if len(p.pending) > 0 {
+		return p.pendingPosition
+	}
+	return p.position
+}
+
+// Seek makes the next call to Next fetch the page at position, as returned
+// by Position, e.g. to resume an interrupted run.
+func (p *Pager[T]) Seek(position string) {
+	p.position = position
+	p.done = false
+	p.pending = nil
+}
+
+// Next fetches the next page, or returns the rest of the page ForEach
+// stopped in. On error the position is unchanged, so Next can be called
+// again to retry the same page.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if len(p.pending) > 0 {
+		page := p.pending
+		p.pending = nil
+		return page, nil
+	}
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+	page, next, err := p.fetch(ctx, p.position)
+	if err != nil {
+		return nil, err
+	}
+	p.position = next
+	p.done = next == ""
+	return page, nil
+}
+
+// ForEach calls fn for every remaining item until fn returns false, the
+// pages run out or fetching a page fails. When fn stops it, the next ForEach
+// or Next continues with the item after the one fn returned false for.
+func (p *Pager[T]) ForEach(ctx context.Context, fn func(T) bool) error {
+	for p.More() {
+		position := p.Position()
+		page, err := p.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for i, item := range page {
+			if !fn(item) {
+				if rest := page[i+1:]; len(rest) > 0 {
+					p.pending, p.pendingPosition = rest, position
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Collect returns all remaining items.
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var all []T
+	err := p.ForEach(ctx, func(item T) bool {
+		all = append(all, item)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// errPager returns a pager whose first page fails with err.
+func errPager[T any](err error) *Pager[T] {
+	return &Pager[T]{fetch: func(context.Context, string) ([]T, string, error) {
+		return nil, "", err
+	}}
+}
+
+// ListOptions narrows and orders a REST collection.
+type ListOptions struct {
+	PageSize int // defaults to DEFAULT_PAGE_SIZE, at most the maxPageSizeAllowed the API reports (MAX_PAGE_SIZE until it has)
+	Filter   *Filter
+	// Sort lists the properties to sort by, prefixed with "-" for descending
+	// order, e.g. []string{"-date", "customer.customerNumber"}.
+	Sort []string
+}
+
+func (opts ListOptions) pageSize(max int) int {
+	switch {
+	case opts.PageSize <= 0:
+		return min(DEFAULT_PAGE_SIZE, max)
+	case opts.PageSize > max:
+		return max
+	}
+	return opts.PageSize
+}
+
+// query returns the filter and sort parameters of the options.
+func (opts ListOptions) query() url.Values {
+	params := filterParams(opts.Filter)
+	if len(opts.Sort) > 0 {
+		params.Set("sort", strings.Join(opts.Sort, ","))
+	}
+	return params
+}
+
+// pager pages through a REST collection (see getEntities) by following the
+// nextPage link of each response. The position is the link of the next page
+// relative to the REST base URL.
+func (tc *TypedClient[T]) pager(baseUrl string, opts ListOptions) *Pager[T] {
+	client := tc.client
+	if err := opts.Filter.Err(); err != nil {
+		return errPager[T](err)
+	}
+	return &Pager[T]{fetch: func(ctx context.Context, position string) ([]T, string, error) {
+		endpoint := position
+		if endpoint == "" {
+			params := opts.query()
+			params.Set("skippages", "0")
+			params.Set("pagesize", strconv.Itoa(opts.pageSize(client.maxPageSize())))
+			endpoint = baseUrl + "?" + params.Encode()
+		}
+		results := CollectionReponse[T]{}
+		err := client.callRestAPI(ctx, endpoint, http.MethodGet, nil, &results)
+		if err != nil {
+			return nil, "", err
+		}
+		if n := results.Pagination.MaxPageSizeAllowed; n > 0 {
+			atomic.StoreInt64(&client.maxPageSizeAllowed, int64(n))
+		}
+		next := ""
+		if results.Pagination.NextPage != "" && len(results.Collection) > 0 {
+			next, err = nextPageEndpoint(client.restBaseURL(), results.Pagination.NextPage, opts)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		return results.Collection, next, nil
+	}}
+}
+
+// nextPageEndpoint turns a nextPage link into an endpoint for callRestAPI,
+// putting back any filter or sort parameter the link lost.
+func nextPageEndpoint(restBaseURL, link string, opts ListOptions) (string, error) {
+	next, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid nextPage link %q: %w", link, err)
+	}
+	base, err := url.Parse(restBaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid REST base URL %q: %w", restBaseURL, err)
+	}
+	path := strings.TrimPrefix(next.Path, strings.TrimSuffix(base.Path, "/"))
+	params := next.Query()
+	for k, v := range opts.query() {
+		if params.Get(k) == "" {
+			params[k] = v
+		}
+	}
+	return strings.TrimPrefix(path, "/") + "?" + params.Encode(), nil
+}
+
+// newCursorPager pages through a cursor-based OpenAPI endpoint (see
+// getAllCursor). The cursor is absent in the response when there are no
+// more items.
+func newCursorPager[T any](client *Client, baseURL string, params url.Values) *Pager[T] {
+	return &Pager[T]{fetch: func(ctx context.Context, cursor string) ([]T, string, error) {
+		p := url.Values{}
+		for k, v := range params {
+			p[k] = v
+		}
+		if cursor != "" {
+			p.Set("cursor", cursor)
+		}
+		resp := CursorResponse[T]{}
+		if err := client.callAPI(ctx, baseURL, http.MethodGet, p, nil, &resp); err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.Cursor, nil
+	}}
+}
+
+// InvoicesPager pages through the invoices of a class ("drafts", "booked",
+// "paid", ...).
+func (client *Client) InvoicesPager(class string, opts ListOptions) *Pager[Invoice] {
+	if err := ValidateInvoiceClass(class); err != nil {
+		return errPager[Invoice](err)
+	}
+	tc := &TypedClient[Invoice]{client: client}
+	return tc.pager("invoices/"+class, opts)
+}
+
+// CustomersPager pages through the customers.
+func (client *Client) CustomersPager(opts ListOptions) *Pager[Customer] {
+	tc := &TypedClient[Customer]{client: client}
+	return tc.pager("customers", opts)
+}
+
+// DraftEntriesPager pages through the journal draft entries, optionally filtered.
+func (client *Client) DraftEntriesPager(filter *Filter) *Pager[JournalEntry] {
+	if err := filter.Err(); err != nil {
+		return errPager[JournalEntry](err)
+	}
+	return newCursorPager[JournalEntry](client, journalDraftEntryBaseUrl, filterParams(filter))
+}
+
+// BookedEntriesPager pages through the booked entries, optionally filtered.
+func (client *Client) BookedEntriesPager(filter *Filter) *Pager[JournalEntry] {
+	if err := filter.Err(); err != nil {
+		return errPager[JournalEntry](err)
+	}
+	return newCursorPager[JournalEntry](client, bookedEntriesApiBaseUrl, filterParams(filter))
+}
+
+func filterParams(filter *Filter) url.Values {
+	params := url.Values{}
+	if s := filter.String(); s != "" {
+		params.Set("filter", s)
+	}
+	return params
+}
@@ -0,0 +1,233 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInvoicesPager(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	for i := 0; i < 25; i++ {
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: MustParseDate("2024-01-02")})
+	}
+	ctx := context.Background()
+
+	pager := client.InvoicesPager("booked", ListOptions{PageSize: 10})
+	var sizes []int
+	for pager.More() {
+		invoices, err := pager.Next(ctx)
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		sizes = append(sizes, len(invoices))
+	}
+	if len(sizes) != 3 || sizes[0] != 10 || sizes[2] != 5 {
+		t.Fatalf("Expected pages of 10, 10 and 5, got %v", sizes)
+	}
+	if _, err := pager.Next(ctx); !errors.Is(err, ErrNoMorePages) {
+		t.Fatalf("Expected ErrNoMorePages, got %v", err)
+	}
+
+	// stop early, then resume from the saved position with a new pager
+	pager = client.InvoicesPager("booked", ListOptions{PageSize: 10})
+	if _, err := pager.Next(ctx); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	saved := pager.Position()
+	resumed := client.InvoicesPager("booked", ListOptions{PageSize: 10})
+	resumed.Seek(saved)
+	rest, err := resumed.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(rest) != 15 || rest[0].BookedInvoiceNumber != 11 {
+		t.Fatalf("Expected invoices 11-25 after resuming, got %d starting at %d", len(rest), rest[0].BookedInvoiceNumber)
+	}
+}
+
+func TestPagerForEachStopsEarly(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	for i := 0; i < 30; i++ {
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: MustParseDate("2024-01-02")})
+	}
+	before := len(srv.Requests())
+	seen := 0
+	pager := client.InvoicesPager("booked", ListOptions{PageSize: 10})
+	err := pager.ForEach(context.Background(), func(Invoice) bool {
+		seen++
+		return seen < 5
+	})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if seen != 5 {
+		t.Fatalf("Expected to stop after 5 invoices, saw %d", seen)
+	}
+	if requests := len(srv.Requests()) - before; requests != 1 {
+		t.Fatalf("Expected a single page request, got %d", requests)
+	}
+	if pager.Position() != "" {
+		t.Fatalf("Expected the position of the unfinished first page, got %s", pager.Position())
+	}
+
+	// resuming continues with the sixth invoice
+	rest, err := pager.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(rest) != 5 || rest[0].BookedInvoiceNumber != 6 {
+		t.Fatalf("Expected invoices 6-10, got %d starting at %d", len(rest), rest[0].BookedInvoiceNumber)
+	}
+	next := 11
+	err = pager.ForEach(context.Background(), func(invoice Invoice) bool {
+		if invoice.BookedInvoiceNumber != next {
+			t.Fatalf("Expected invoice %d, got %d", next, invoice.BookedInvoiceNumber)
+		}
+		next++
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if next != 31 {
+		t.Fatalf("Expected all 30 invoices, stopped before %d", next)
+	}
+}
+
+func TestDraftEntriesPager(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.CursorPageSize = 2
+	for i := 0; i < 5; i++ {
+		srv.Add("draft-entries", JournalEntry{JournalNumber: 1, Date: MustParseDate("2024-01-02"), Amount: MustParseAmount("100")})
+	}
+	ctx := context.Background()
+	pager := client.DraftEntriesPager(nil)
+	first, err := pager.Next(ctx)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(first) != 2 || !pager.More() || pager.Position() == "" {
+		t.Fatalf("Expected a first page of 2 with a cursor, got %d (cursor %q)", len(first), pager.Position())
+	}
+
+	resumed := client.DraftEntriesPager(nil)
+	resumed.Seek(pager.Position())
+	rest, err := resumed.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(rest) != 3 {
+		t.Fatalf("Expected the remaining 3 entries, got %d", len(rest))
+	}
+}
+
+func TestGetEntitiesKeepsFilterOnEveryPage(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	for i := 0; i < 25; i++ {
+		date := NewDate(2024, 1, 2)
+		if i%2 == 0 {
+			date = NewDate(2024, 6, 2)
+		}
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: date})
+	}
+	filter := &Filter{}
+	filter.AndCondition("date", FilterOperatorGreaterThanOrEqual, "2024-06-01")
+	tc := &TypedClient[Invoice]{client: client}
+	invoices, err := tc.getEntities(context.Background(), "invoices/booked", ListOptions{PageSize: 5, Filter: filter})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(invoices) != 13 {
+		t.Fatalf("Expected 13 invoices, got %d", len(invoices))
+	}
+	for _, invoice := range invoices {
+		if invoice.Date != NewDate(2024, 6, 2) {
+			t.Fatalf("Expected only filtered invoices, got %s", invoice.Date)
+		}
+	}
+	for _, r := range srv.Requests() {
+		if !strings.Contains(r.Query, "filter=") {
+			t.Fatalf("Expected the filter on every page, got %s", r.Query)
+		}
+	}
+}
+
+func TestGetEntitiesExactMultipleOfPageSize(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	for i := 0; i < 20; i++ {
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: MustParseDate("2024-01-02")})
+	}
+	before := len(srv.Requests())
+	tc := &TypedClient[Invoice]{client: client}
+	invoices, err := tc.getEntities(context.Background(), "invoices/booked", ListOptions{PageSize: 10})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(invoices) != 20 {
+		t.Fatalf("Expected 20 invoices, got %d", len(invoices))
+	}
+	if requests := len(srv.Requests()) - before; requests != 2 {
+		t.Fatalf("Expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestListOptionsSortAndPageSize(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	for i := 0; i < 12; i++ {
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: MustParseDate("2024-01-02")})
+	}
+	invoices, err := client.InvoicesPager("booked", ListOptions{PageSize: 5, Sort: []string{"-bookedInvoiceNumber"}}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	for i, invoice := range invoices {
+		if invoice.BookedInvoiceNumber != 12-i {
+			t.Fatalf("Expected descending invoice numbers, got %d at %d", invoice.BookedInvoiceNumber, i)
+		}
+	}
+
+	before := len(srv.Requests())
+	if _, err := client.InvoicesPager("booked", ListOptions{PageSize: 5000}).Next(context.Background()); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if query := srv.Requests()[before].Query; !strings.Contains(query, "pagesize=1000") {
+		t.Fatalf("Expected the page size to be capped at 1000, got %s", query)
+	}
+}
+
+func TestPageSizeFollowsServerMaximum(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.MaxPageSize = 8
+	for i := 0; i < 12; i++ {
+		srv.Add("invoices/booked", Invoice{Currency: "DKK", Date: MustParseDate("2024-01-02")})
+	}
+	if _, err := client.InvoicesPager("booked", ListOptions{PageSize: 5}).Next(context.Background()); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	before := len(srv.Requests())
+	invoices, err := client.InvoicesPager("booked", ListOptions{PageSize: 500}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(invoices) != 12 {
+		t.Fatalf("Expected 12 invoices, got %d", len(invoices))
+	}
+	if query := srv.Requests()[before].Query; !strings.Contains(query, "pagesize=8") {
+		t.Fatalf("Expected the page size to be capped at the reported 8, got %s", query)
+	}
+}
+
+func TestNextPageEndpointRestoresFilter(t *testing.T) {
+	filter := &Filter{}
+	filter.AndCondition("currency", FilterOperatorEquals, "DKK")
+	endpoint, err := nextPageEndpoint("https://restapi.e-conomic.com", "https://restapi.e-conomic.com/invoices/booked?skippages=1&pagesize=20", ListOptions{Filter: filter, Sort: []string{"date"}})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	expected := "invoices/booked?filter=currency%24eq%3ADKK&pagesize=20&skippages=1&sort=date"
+	if endpoint != expected {
+		t.Fatalf("Expected %s, got %s", expected, endpoint)
+	}
+}
@@ -1,16 +1,16 @@
 package economic
 
 import (
-	"net/http"
+	"context"
 )
 
 func (client *Client) GetPaymentTerms() ([]PaymentTerm, error) {
-	var paymentTerms CollectionReponse[PaymentTerm]
-	err := client.callRestAPI("payment-terms", http.MethodGet, nil, &paymentTerms)
-	if err != nil {
-		return paymentTerms.Collection, err
-	}
-	return paymentTerms.Collection, nil
+	return client.GetPaymentTermsContext(context.Background())
+}
+
+func (client *Client) GetPaymentTermsContext(ctx context.Context) ([]PaymentTerm, error) {
+	tc := &TypedClient[PaymentTerm]{client: client}
+	return tc.getEntities(ctx, "payment-terms", ListOptions{})
 }
 
 // PaymentTerm represents a specific payment term on the agreement.
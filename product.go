@@ -0,0 +1,247 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Product is a product or service, also used to reference one on an order
+// line, where only ProductNumber needs to be set.
+type Product struct {
+	ProductNumber            string                    `json:"productNumber,omitempty"`            // The unique product number. This can be a stock keeping unit identifier (SKU).
+	Name                     string                    `json:"name,omitempty"`                     // Descriptive name of the product.
+	Description              string                    `json:"description,omitempty"`              // Free text description of the product.
+	BarCode                  string                    `json:"barCode,omitempty"`                  // String representation of a machine readable barcode symbol that represents this product.
+	Barred                   bool                      `json:"barred,omitempty"`                   // If this value is true, then the product can no longer be sold, and trying to book an invoice with this product will not be possible.
+	CostPrice                *Amount                   `json:"costPrice,omitempty"`                // The cost of the goods. If you have the inventory module enabled, this is read-only and will just be ignored.
+	RecommendedPrice         *Amount                   `json:"recommendedPrice,omitempty"`         // Recommended retail price of the goods.
+	SalesPrice               *Amount                   `json:"salesPrice,omitempty"`               // This is the unit net price that will appear on invoice lines when a product is added to an invoice line.
+	LastUpdated              string                    `json:"lastUpdated,omitempty"`              // The last time the product was updated, either directly or through inventory changed.
+	ProductGroup             *ProductGroup             `json:"productGroup,omitempty"`             // A reference to the product group this product is contained within. Required when creating a product.
+	Unit                     *Unit                     `json:"unit,omitempty"`                     // A reference to the unit this product is counted in.
+	Inventory                *ProductInventory         `json:"inventory,omitempty"`                // A collection of properties that are only applicable if the inventory module is enabled.
+	DepartmentalDistribution *DepartmentalDistribution `json:"departmentalDistribution,omitempty"` // A departmental distribution defines which departments this entry is distributed between. This requires the departments module to be enabled.
+	Self                     string                    `json:"self,omitempty"`                     // A unique reference to the product resource.
+}
+
+// ProductInventory is only available with the inventory module. It is read-only.
+type ProductInventory struct {
+	Available            float64 `json:"available"`            // The number of units available to sell, i.e. in stock minus ordered by customers.
+	InStock              float64 `json:"inStock"`              // The number of units in stock including any that have been ordered by customers.
+	OrderedByCustomers   float64 `json:"orderedByCustomers"`   // The number of units that have been ordered by customers, but haven't been sold yet.
+	OrderedFromSuppliers float64 `json:"orderedFromSuppliers"` // The number of units that have been ordered from your suppliers, but haven't been delivered to you yet.
+	GrossWeight          float64 `json:"grossWeight"`          // The gross weight of the product.
+	NetWeight            float64 `json:"netWeight"`            // The net weight of the product.
+	PackageVolume        float64 `json:"packageVolume"`        // The volume the shipped package makes up.
+	RecommendedCostPrice Amount  `json:"recommendedCostPrice"` // The recommendedCostPrice of the product.
+}
+
+// ProductGroup groups products that are booked to the same sales accounts.
+type ProductGroup struct {
+	ProductGroupNumber int    `json:"productGroupNumber"`         // Unique number identifying the product group.
+	Name               string `json:"name,omitempty"`             // Descriptive name of the product group.
+	InventoryEnabled   bool   `json:"inventoryEnabled,omitempty"` // States if the product group is inventory enabled or not.
+	Self               string `json:"self,omitempty"`             // A unique reference to the product group resource.
+}
+
+// ProductPrice is the sales price of a product in a currency other than the
+// agreement's base currency.
+type ProductPrice struct {
+	Currency struct {
+		Code string `json:"code"` // The ISO 4217 3-letter currency code.
+	} `json:"currency"`
+	Price Amount `json:"price"` // The unit net price in the currency.
+	Self  string `json:"self,omitempty"`
+}
+
+func productUrl(productNumber string) string {
+	return "products/" + url.PathEscape(productNumber)
+}
+
+func productPricesUrl(productNumber string) string {
+	return productUrl(productNumber) + "/pricing/currency-specific-sales-prices"
+}
+
+func (client *Client) GetProducts() ([]Product, error) {
+	return client.GetProductsContext(context.Background())
+}
+
+func (client *Client) GetProductsContext(ctx context.Context) ([]Product, error) {
+	tc := &TypedClient[Product]{client: client}
+	return tc.getEntities(ctx, "products", ListOptions{PageSize: MAX_PAGE_SIZE})
+}
+
+// ProductsPager pages through the products, e.g. to sync a large catalogue.
+func (client *Client) ProductsPager(opts ListOptions) *Pager[Product] {
+	tc := &TypedClient[Product]{client: client}
+	return tc.pager("products", opts)
+}
+
+func (client *Client) GetProduct(productNumber string) (*Product, error) {
+	return client.GetProductContext(context.Background(), productNumber)
+}
+
+func (client *Client) GetProductContext(ctx context.Context, productNumber string) (*Product, error) {
+	var product Product
+	err := client.callRestAPI(ctx, productUrl(productNumber), http.MethodGet, nil, &product)
+	return &product, err
+}
+
+func (client *Client) CreateProduct(product *Product) (*Product, error) {
+	return client.CreateProductContext(context.Background(), product)
+}
+
+// CreateProductContext creates a product. ProductNumber, Name and
+// ProductGroup are required.
+func (client *Client) CreateProductContext(ctx context.Context, product *Product) (*Product, error) {
+	if product == nil || product.ProductNumber == "" || product.Name == "" || product.ProductGroup == nil {
+		return nil, fmt.Errorf("a product needs a product number, a name and a product group")
+	}
+	var created Product
+	err := client.callRestAPI(ctx, "products", http.MethodPost, product, &created, client.productLookup(product.ProductNumber, &created))
+	return &created, err
+}
+
+// productLookup finds a product created by a failed CreateProduct.
+func (client *Client) productLookup(productNumber string, product *Product) lookupFunc {
+	return func(ctx context.Context) (bool, error) {
+		p, err := client.GetProductContext(ctx, productNumber)
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		*product = *p
+		return true, nil
+	}
+}
+
+func (client *Client) UpdateProduct(product *Product) (*Product, error) {
+	return client.UpdateProductContext(context.Background(), product)
+}
+
+// UpdateProductContext replaces the product with the same ProductNumber.
+func (client *Client) UpdateProductContext(ctx context.Context, product *Product) (*Product, error) {
+	var updated Product
+	err := client.callRestAPI(ctx, productUrl(product.ProductNumber), http.MethodPut, product, &updated)
+	return &updated, err
+}
+
+// CreateOrUpdateProduct updates the product if it exists and creates it
+// otherwise, e.g. to sync a catalogue.
+func (client *Client) CreateOrUpdateProduct(product *Product) (*Product, error) {
+	return client.CreateOrUpdateProductContext(context.Background(), product)
+}
+
+func (client *Client) CreateOrUpdateProductContext(ctx context.Context, product *Product) (*Product, error) {
+	_, err := client.GetProductContext(ctx, product.ProductNumber)
+	if IsNotFound(err) {
+		return client.CreateProductContext(ctx, product)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateProductContext(ctx, product)
+}
+
+func (client *Client) DeleteProduct(productNumber string) error {
+	return client.DeleteProductContext(context.Background(), productNumber)
+}
+
+func (client *Client) DeleteProductContext(ctx context.Context, productNumber string) error {
+	return client.callRestAPI(ctx, productUrl(productNumber), http.MethodDelete, nil, nil)
+}
+
+func (client *Client) GetProductGroups() ([]ProductGroup, error) {
+	return client.GetProductGroupsContext(context.Background())
+}
+
+func (client *Client) GetProductGroupsContext(ctx context.Context) ([]ProductGroup, error) {
+	tc := &TypedClient[ProductGroup]{client: client}
+	return tc.getEntities(ctx, "product-groups", ListOptions{})
+}
+
+func (client *Client) GetProductGroup(number int) (*ProductGroup, error) {
+	return client.GetProductGroupContext(context.Background(), number)
+}
+
+func (client *Client) GetProductGroupContext(ctx context.Context, number int) (*ProductGroup, error) {
+	var group ProductGroup
+	err := client.callRestAPI(ctx, fmt.Sprintf("product-groups/%d", number), http.MethodGet, nil, &group)
+	return &group, err
+}
+
+func (client *Client) GetProductsInGroup(number int) ([]Product, error) {
+	return client.GetProductsInGroupContext(context.Background(), number)
+}
+
+func (client *Client) GetProductsInGroupContext(ctx context.Context, number int) ([]Product, error) {
+	tc := &TypedClient[Product]{client: client}
+	return tc.getEntities(ctx, fmt.Sprintf("product-groups/%d/products", number), ListOptions{PageSize: MAX_PAGE_SIZE})
+}
+
+// GetProductPrices returns the currency specific sales prices of a product.
+// The price in the base currency is Product.SalesPrice.
+func (client *Client) GetProductPrices(productNumber string) ([]ProductPrice, error) {
+	return client.GetProductPricesContext(context.Background(), productNumber)
+}
+
+func (client *Client) GetProductPricesContext(ctx context.Context, productNumber string) ([]ProductPrice, error) {
+	tc := &TypedClient[ProductPrice]{client: client}
+	return tc.getEntities(ctx, productPricesUrl(productNumber), ListOptions{})
+}
+
+// SetProductPrice sets the sales price of a product in currency, creating
+// or replacing the currency specific price.
+func (client *Client) SetProductPrice(productNumber, currency string, price Amount) (*ProductPrice, error) {
+	return client.SetProductPriceContext(context.Background(), productNumber, currency, price)
+}
+
+func (client *Client) SetProductPriceContext(ctx context.Context, productNumber, currency string, price Amount) (*ProductPrice, error) {
+	currency = strings.ToUpper(currency)
+	body := ProductPrice{Price: price}
+	body.Currency.Code = currency
+	var set ProductPrice
+	err := client.callRestAPI(ctx, productPricesUrl(productNumber)+"/"+url.PathEscape(currency), http.MethodPut, body, &set)
+	return &set, err
+}
+
+func (client *Client) DeleteProductPrice(productNumber, currency string) error {
+	return client.DeleteProductPriceContext(context.Background(), productNumber, currency)
+}
+
+func (client *Client) DeleteProductPriceContext(ctx context.Context, productNumber, currency string) error {
+	return client.callRestAPI(ctx, productPricesUrl(productNumber)+"/"+url.PathEscape(strings.ToUpper(currency)), http.MethodDelete, nil, nil)
+}
+
+// ValidateOrderLines checks that the products on the lines exist and are not
+// barred, so an order fails before it reaches e-conomic rather than when it
+// is booked.
+func (client *Client) ValidateOrderLines(lines []OrderLine) error {
+	return client.ValidateOrderLinesContext(context.Background(), lines)
+}
+
+func (client *Client) ValidateOrderLinesContext(ctx context.Context, lines []OrderLine) error {
+	checked := map[string]bool{}
+	for _, line := range lines {
+		if line.Product == nil || line.Product.ProductNumber == "" || checked[line.Product.ProductNumber] {
+			continue
+		}
+		product, err := client.GetProductContext(ctx, line.Product.ProductNumber)
+		if IsNotFound(err) {
+			return fmt.Errorf("line %d: product %s does not exist", line.LineNumber, line.Product.ProductNumber)
+		}
+		if err != nil {
+			return err
+		}
+		if product.Barred {
+			return fmt.Errorf("line %d: product %s is barred", line.LineNumber, line.Product.ProductNumber)
+		}
+		checked[line.Product.ProductNumber] = true
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package economic
+
+import (
+	"testing"
+)
+
+func TestProducts(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("product-groups", ProductGroup{ProductGroupNumber: 1, Name: "Varer"})
+
+	if _, err := client.CreateProduct(&Product{ProductNumber: "SKU-0"}); err == nil {
+		t.Fatalf("Expected an error creating a product without name and group")
+	}
+	product := &Product{ProductNumber: "SKU-1", Name: "Widget", SalesPrice: MustParseAmount("100").Ptr(), ProductGroup: &ProductGroup{ProductGroupNumber: 1}}
+	created, err := client.CreateProduct(product)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if created.ProductNumber != "SKU-1" || created.Self == "" {
+		t.Fatalf("Expected the created product, got %+v", created)
+	}
+
+	product.Barred = true
+	if _, err := client.CreateOrUpdateProduct(product); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	got, err := client.GetProduct("SKU-1")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !got.Barred || got.Name != "Widget" {
+		t.Fatalf("Expected the updated product, got %+v", got)
+	}
+
+	inGroup, err := client.GetProductsInGroup(1)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(inGroup) != 1 || inGroup[0].ProductNumber != "SKU-1" {
+		t.Fatalf("Expected SKU-1 in group 1, got %+v", inGroup)
+	}
+	groups, err := client.GetProductGroups()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(groups) != 1 || groups[0].Name != "Varer" {
+		t.Fatalf("Expected group Varer, got %+v", groups)
+	}
+
+	if err := client.DeleteProduct("SKU-1"); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.GetProduct("SKU-1"); !IsNotFound(err) {
+		t.Fatalf("Expected the product to be deleted, got %v", err)
+	}
+}
+
+func TestProductPrices(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("products", Product{ProductNumber: "SKU-2", Name: "Gadget"})
+
+	if _, err := client.SetProductPrice("SKU-2", "eur", MustParseAmount("12.5")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.SetProductPrice("SKU-2", "EUR", MustParseAmount("13")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.SetProductPrice("SKU-2", "USD", MustParseAmount("15")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	prices, err := client.GetProductPrices("SKU-2")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(prices) != 2 || prices[0].Currency.Code != "EUR" || prices[0].Price != MustParseAmount("13") {
+		t.Fatalf("Expected EUR 13 and USD 15, got %+v", prices)
+	}
+	if err := client.DeleteProductPrice("SKU-2", "USD"); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.SetProductPrice("SKU-404", "EUR", MustParseAmount("1")); !IsNotFound(err) {
+		t.Fatalf("Expected a missing product to give 404, got %v", err)
+	}
+}
+
+func TestValidateOrderLines(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("products", Product{ProductNumber: "OK", Name: "Sellable"}, Product{ProductNumber: "OLD", Name: "Discontinued", Barred: true})
+
+	lines := []OrderLine{{LineNumber: 1, Product: &Product{ProductNumber: "OK"}}, {LineNumber: 2, Description: "Freight"}}
+	if err := client.ValidateOrderLines(lines); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if err := client.ValidateOrderLines(append(lines, OrderLine{LineNumber: 3, Product: &Product{ProductNumber: "OLD"}})); err == nil {
+		t.Fatalf("Expected a barred product to fail validation")
+	}
+	if err := client.ValidateOrderLines([]OrderLine{{LineNumber: 1, Product: &Product{ProductNumber: "NOPE"}}}); err == nil {
+		t.Fatalf("Expected a missing product to fail validation")
+	}
+}
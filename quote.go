@@ -0,0 +1,194 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Quote is a quotation. It has the same contents as an Order; only the
+// number differs.
+type Quote struct {
+	QuoteNumber int `json:"quoteNumber,omitempty"` // A reference number for the quote document. e-conomic assigns it.
+	Order
+}
+
+// Quotes move from "drafts" to "sent" when they are marked as sent, and to
+// "archived" once e-conomic has turned them into an order or invoice.
+var quoteClasses = []string{"drafts", "sent", "archived"}
+
+func ValidateQuoteClass(class string) error {
+	for _, c := range quoteClasses {
+		if c == class {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid quote class '%s'", class)
+}
+
+func (client *Client) GetQuotes(class string) ([]Quote, error) {
+	return client.GetQuotesContext(context.Background(), class)
+}
+
+func (client *Client) GetQuotesContext(ctx context.Context, class string) ([]Quote, error) {
+	return client.QuotesPager(class, ListOptions{PageSize: MAX_PAGE_SIZE}).Collect(ctx)
+}
+
+// QuotesPager pages through the quotes of a class ("drafts", "sent" or
+// "archived").
+func (client *Client) QuotesPager(class string, opts ListOptions) *Pager[Quote] {
+	if err := ValidateQuoteClass(class); err != nil {
+		return errPager[Quote](err)
+	}
+	tc := &TypedClient[Quote]{client: client}
+	return tc.pager("quotes/"+class, opts)
+}
+
+func (client *Client) GetQuote(class string, quoteNo int) (Quote, error) {
+	return client.GetQuoteContext(context.Background(), class, quoteNo)
+}
+
+func (client *Client) GetQuoteContext(ctx context.Context, class string, quoteNo int) (quote Quote, err error) {
+	if err = ValidateQuoteClass(class); err != nil {
+		return
+	}
+	err = client.callRestAPI(ctx, fmt.Sprintf("quotes/%s/%d", class, quoteNo), http.MethodGet, nil, &quote)
+	return
+}
+
+func (client *Client) CreateQuote(quote *Quote) (Quote, error) {
+	return client.CreateQuoteContext(context.Background(), quote)
+}
+
+// CreateQuoteContext creates a draft quote. Like CreateOrder, it is only
+// retried after a 5xx or network error when the quote has references.other
+// or ExternalId to look it up by.
+func (client *Client) CreateQuoteContext(ctx context.Context, quote *Quote) (created Quote, err error) {
+	err = client.callRestAPI(ctx, "quotes/drafts", http.MethodPost, quote, &created, client.draftQuoteLookup(quote, &created))
+	return
+}
+
+// draftQuoteLookup is draftOrderLookup for draft quotes.
+func (client *Client) draftQuoteLookup(quote *Quote, created *Quote) lookupFunc {
+	ref := ""
+	if quote.References != nil {
+		ref = quote.References.Other
+	}
+	if ref == "" && quote.ExternalId == "" {
+		return nil
+	}
+	return func(ctx context.Context) (bool, error) {
+		tc := &TypedClient[Quote]{client: client}
+		drafts, err := tc.getEntities(ctx, "quotes/drafts", ListOptions{PageSize: MAX_PAGE_SIZE, Filter: sameOrderFilter(&quote.Order, ref)})
+		if err != nil {
+			return false, err
+		}
+		found := false
+		for _, draft := range drafts {
+			if quote.ExternalId != "" && draft.ExternalId != quote.ExternalId {
+				continue
+			}
+			if !found || draft.QuoteNumber > created.QuoteNumber {
+				*created = draft
+				found = true
+			}
+		}
+		return found, nil
+	}
+}
+
+func (client *Client) UpdateDraftQuote(quote *Quote) (Quote, error) {
+	return client.UpdateDraftQuoteContext(context.Background(), quote)
+}
+
+// UpdateDraftQuoteContext replaces the draft quote with the same QuoteNumber.
+func (client *Client) UpdateDraftQuoteContext(ctx context.Context, quote *Quote) (updated Quote, err error) {
+	if quote.QuoteNumber == 0 {
+		return updated, fmt.Errorf("cannot update a draft quote without a quote number")
+	}
+	err = client.callRestAPI(ctx, fmt.Sprintf("quotes/drafts/%d", quote.QuoteNumber), http.MethodPut, quote, &updated)
+	return
+}
+
+func (client *Client) DeleteDraftQuote(quoteNo int) error {
+	return client.DeleteDraftQuoteContext(context.Background(), quoteNo)
+}
+
+func (client *Client) DeleteDraftQuoteContext(ctx context.Context, quoteNo int) error {
+	return client.callRestAPI(ctx, fmt.Sprintf("quotes/drafts/%d", quoteNo), http.MethodDelete, nil, nil)
+}
+
+func (client *Client) MarkQuoteAsSent(quoteNo int) (Quote, error) {
+	return client.MarkQuoteAsSentContext(context.Background(), quoteNo)
+}
+
+// MarkQuoteAsSentContext moves a draft quote to the sent quotes.
+func (client *Client) MarkQuoteAsSentContext(ctx context.Context, quoteNo int) (sent Quote, err error) {
+	draft, err := client.GetQuoteContext(ctx, "drafts", quoteNo)
+	if err != nil {
+		return sent, err
+	}
+	lookup := func(ctx context.Context) (bool, error) {
+		quote, err := client.GetQuoteContext(ctx, "sent", quoteNo)
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		sent = quote
+		return true, nil
+	}
+	err = client.callRestAPI(ctx, "quotes/sent", http.MethodPost, draft, &sent, lookup)
+	return
+}
+
+func (client *Client) DownloadQuotePdf(class string, quoteNo int, w io.Writer) error {
+	return client.DownloadQuotePdfContext(context.Background(), class, quoteNo, w)
+}
+
+// DownloadQuotePdfContext streams the PDF of a quote in class to w.
+func (client *Client) DownloadQuotePdfContext(ctx context.Context, class string, quoteNo int, w io.Writer) error {
+	if err := ValidateQuoteClass(class); err != nil {
+		return err
+	}
+	_, err := client.download(ctx, fmt.Sprintf("quotes/%s/%d/pdf", class, quoteNo), w)
+	return err
+}
+
+func (client *Client) ConvertQuoteToOrder(class string, quoteNo int) (Order, error) {
+	return client.ConvertQuoteToOrderContext(context.Background(), class, quoteNo)
+}
+
+// ConvertQuoteToOrderContext creates a draft order from an accepted quote,
+// keeping its lines, references, notes, delivery and project. The quote
+// itself is left as it is.
+func (client *Client) ConvertQuoteToOrderContext(ctx context.Context, class string, quoteNo int) (order Order, err error) {
+	quote, err := client.getConvertibleQuote(ctx, class, quoteNo)
+	if err != nil {
+		return order, err
+	}
+	return client.CreateOrderContext(ctx, quote.copyContents())
+}
+
+func (client *Client) ConvertQuoteToInvoice(class string, quoteNo int) (Invoice, error) {
+	return client.ConvertQuoteToInvoiceContext(context.Background(), class, quoteNo)
+}
+
+// ConvertQuoteToInvoiceContext is ConvertQuoteToOrderContext for when the
+// order step is skipped and the quote is invoiced directly.
+func (client *Client) ConvertQuoteToInvoiceContext(ctx context.Context, class string, quoteNo int) (invoice Invoice, err error) {
+	quote, err := client.getConvertibleQuote(ctx, class, quoteNo)
+	if err != nil {
+		return invoice, err
+	}
+	return client.CreateInvoiceContext(ctx, quote.copyContents())
+}
+
+func (client *Client) getConvertibleQuote(ctx context.Context, class string, quoteNo int) (Quote, error) {
+	if class != "drafts" && class != "sent" {
+		return Quote{}, fmt.Errorf("only draft and sent quotes can be converted, not '%s'", class)
+	}
+	return client.GetQuoteContext(ctx, class, quoteNo)
+}
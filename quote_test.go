@@ -0,0 +1,61 @@
+package economic
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestQuoteToOrder(t *testing.T) {
+	client := getTestClient(t)
+	quote := &Quote{Order: *testOrder("quote-1")}
+	quote.Notes = &Notes{Heading: "Offer"}
+	quote.Delivery = &Delivery{Address: "Lagervej 2", City: "Aarhus"}
+	quote.Project = &Project{ProjectNumber: 7}
+	quote.Lines = []OrderLine{{LineNumber: 1, Description: "Licence", Quantity: 1, UnitNetPrice: MustParseAmount("250").Ptr()}}
+	draft, err := client.CreateQuote(quote)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if draft.QuoteNumber == 0 || draft.OrderNumber != 0 {
+		t.Fatalf("Expected a quote number, got %+v", draft)
+	}
+	sent, err := client.MarkQuoteAsSent(draft.QuoteNumber)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.GetQuote("drafts", draft.QuoteNumber); !IsNotFound(err) {
+		t.Fatalf("Expected the draft to be gone, got %v", err)
+	}
+	var pdf bytes.Buffer
+	if err := client.DownloadQuotePdf("sent", sent.QuoteNumber, &pdf); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF")) {
+		t.Fatalf("Expected a PDF, got %q", pdf.String())
+	}
+
+	order, err := client.ConvertQuoteToOrder("sent", sent.QuoteNumber)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if order.OrderNumber == 0 || order.References == nil || order.References.Other != "quote-1" {
+		t.Fatalf("Expected an order with the quote's reference, got %+v", order)
+	}
+	if order.Notes == nil || order.Notes.Heading != "Offer" || order.Delivery == nil || order.Delivery.City != "Aarhus" || order.Project == nil || order.Project.ProjectNumber != 7 {
+		t.Fatalf("Expected notes, delivery and project to be kept, got %+v", order)
+	}
+	if len(order.Lines) != 1 || order.Lines[0].Description != "Licence" {
+		t.Fatalf("Expected the quote's lines, got %+v", order.Lines)
+	}
+
+	invoice, err := client.ConvertQuoteToInvoice("sent", sent.QuoteNumber)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if invoice.NetAmount != MustParseAmount("250") {
+		t.Fatalf("Expected the quote's lines on the invoice, got net amount %v", invoice.NetAmount)
+	}
+	if _, err := client.ConvertQuoteToOrder("archived", sent.QuoteNumber); err == nil {
+		t.Fatalf("Expected archived quotes not to be converted")
+	}
+}
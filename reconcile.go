@@ -0,0 +1,388 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReconcileStatus is the outcome of matching a payment to an invoice.
+type ReconcileStatus string
+
+const (
+	ReconcileMatched   ReconcileStatus = "matched"   // The payment pays what was left of the invoice.
+	ReconcilePartial   ReconcileStatus = "partial"   // The payment pays part of what was left.
+	ReconcileOverpaid  ReconcileStatus = "overpaid"  // The payment is more than what was left.
+	ReconcileUnmatched ReconcileStatus = "unmatched" // No invoice, or more than one, fits the payment.
+)
+
+// How a payment was matched to its invoice, strongest first.
+const (
+	MatchedByPaymentId     = "payment id"
+	MatchedByReference     = "reference"
+	MatchedByInvoiceNumber = "invoice number"
+	MatchedByAmount        = "amount"
+)
+
+const defaultPaymentIdLength = 15
+
+// ReconcileOptions configures Reconcile.
+type ReconcileOptions struct {
+	// PaymentIdLength is the number of digits, check digit included, of the
+	// OCR/FIK payment ids on the invoices (see FikPaymentId). Defaults to 15,
+	// as used with FIK card type 71.
+	PaymentIdLength int
+	// NoAmountMatch turns off matching on the amount alone, for payments
+	// whose text names no invoice.
+	NoAmountMatch bool
+	// IncomingNegative is set when incoming payments have negative amounts,
+	// e.g. entries on a customer account. By default payments are bank
+	// entries, where incoming payments are positive.
+	IncomingNegative bool
+}
+
+// PaymentMatch is the result of reconciling one payment.
+type PaymentMatch struct {
+	Payment    JournalEntry
+	Invoice    *Invoice // The invoice the payment was matched to, as it was before the payment, or nil.
+	Status     ReconcileStatus
+	MatchedBy  string // One of the MatchedBy constants.
+	Amount     Amount // The part of the payment that settles the invoice.
+	Difference Amount // The payment minus what was left of the invoice; negative when partial.
+	Reason     string // Why the payment is unmatched.
+}
+
+// ReconcileReport is the result of Reconcile.
+type ReconcileReport struct {
+	Matches      []PaymentMatch // One per payment, in the order given.
+	OpenInvoices []Invoice      // Invoices still open after the payments, with their Remainder reduced.
+}
+
+func (r *ReconcileReport) byStatus(status ReconcileStatus) []PaymentMatch {
+	matches := []PaymentMatch{}
+	for _, m := range r.Matches {
+		if m.Status == status {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+func (r *ReconcileReport) Matched() []PaymentMatch   { return r.byStatus(ReconcileMatched) }
+func (r *ReconcileReport) Partial() []PaymentMatch   { return r.byStatus(ReconcilePartial) }
+func (r *ReconcileReport) Overpaid() []PaymentMatch  { return r.byStatus(ReconcileOverpaid) }
+func (r *ReconcileReport) Unmatched() []PaymentMatch { return r.byStatus(ReconcileUnmatched) }
+
+// FikPaymentId returns the payment id of a booked invoice as printed in an
+// OCR/FIK line: the invoice number padded with zeros to length-1 digits,
+// followed by a modulus 10 check digit.
+func FikPaymentId(invoiceNumber, length int) string {
+	if length <= 1 {
+		length = defaultPaymentIdLength
+	}
+	digits := fmt.Sprintf("%0*d", length-1, invoiceNumber)
+	return digits + strconv.Itoa(mod10CheckDigit(digits))
+}
+
+func mod10CheckDigit(digits string) int {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+var digitRuns = regexp.MustCompile(`\d+`)
+
+// paymentIdInvoices returns the invoice numbers of the valid payment ids in
+// text.
+func paymentIdInvoices(text string, length int) []int {
+	var numbers []int
+	for _, run := range digitRuns.FindAllString(text, -1) {
+		if len(run) != length || mod10CheckDigit(run[:length-1]) != int(run[length-1]-'0') {
+			continue
+		}
+		if n, err := strconv.Atoi(run[:length-1]); err == nil && n != 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+var invoiceNumberInText = regexp.MustCompile(`(?i)\b(?:faktura|fakt|invoice|inv)\.?\s*(?:nr\.?|no\.?)?\s*#?\s*(\d+)`)
+
+// containsWord reports whether word occurs in text, ignoring case, and not
+// as part of a longer word or number.
+func containsWord(text, word string) bool {
+	text, word = strings.ToLower(text), strings.ToLower(word)
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func invoiceCustomerNumber(invoice *Invoice) int {
+	if invoice.Customer != nil {
+		return invoice.Customer.CustomerNumber
+	}
+	return invoice.CustomerNumber
+}
+
+// Reconcile matches payments, such as the entries from GetJournalEntries, to
+// the open booked invoices, such as the unpaid ones. A payment is matched, in
+// this order, by an OCR/FIK payment id in its text, by an invoice's
+// references.other in its text, by "faktura 1042" or "invoice 1042" in its
+// text, or by being the only open invoice with exactly its amount. Payments
+// with a CustomerNumber only match that customer's invoices. Only incoming
+// payments are matched (see ReconcileOptions.IncomingNegative); refunds and
+// other outgoing payments are left unmatched.
+//
+// Payments are applied in the order given, so several payments can settle
+// one invoice between them.
+func Reconcile(invoices []Invoice, payments []JournalEntry, opts ReconcileOptions) *ReconcileReport {
+	length := opts.PaymentIdLength
+	if length <= 1 {
+		length = defaultPaymentIdLength
+	}
+	open := []*Invoice{}
+	byNumber := map[int]*Invoice{}
+	for _, invoice := range invoices {
+		// drafts cannot be paid, and credit notes are not paid by the customer
+		if invoice.BookedInvoiceNumber == 0 || invoice.Remainder.Sign() <= 0 || byNumber[invoice.BookedInvoiceNumber] != nil {
+			continue
+		}
+		open = append(open, &invoice)
+		byNumber[invoice.BookedInvoiceNumber] = &invoice
+	}
+
+	report := &ReconcileReport{Matches: make([]PaymentMatch, len(payments))}
+	for i, payment := range payments {
+		m := &report.Matches[i]
+		m.Payment = payment
+		m.Status = ReconcileUnmatched
+		paid := payment.Amount
+		if opts.IncomingNegative {
+			paid = paid.Neg()
+		}
+		if paid.Sign() <= 0 {
+			m.Reason = "not an incoming payment"
+			continue
+		}
+		eligible := func(invoice *Invoice) bool {
+			return invoice != nil && (payment.CustomerNumber == 0 || invoiceCustomerNumber(invoice) == payment.CustomerNumber)
+		}
+		invoice, reason := matchPayment(payment, paid, open, byNumber, eligible, length, opts, m)
+		if invoice == nil {
+			m.Reason = reason
+			continue
+		}
+		if payment.Currency != "" && invoice.Currency != "" && !strings.EqualFold(payment.Currency, invoice.Currency) {
+			m.MatchedBy = ""
+			m.Reason = fmt.Sprintf("payment in %s for invoice %d in %s", payment.Currency, invoice.BookedInvoiceNumber, invoice.Currency)
+			continue
+		}
+		m.Difference = paid.Sub(invoice.Remainder)
+		switch m.Difference.Sign() {
+		case 0:
+			m.Status, m.Amount = ReconcileMatched, paid
+		case -1:
+			m.Status, m.Amount = ReconcilePartial, paid
+		default:
+			m.Status, m.Amount = ReconcileOverpaid, invoice.Remainder
+		}
+		matched := *invoice
+		m.Invoice = &matched
+		invoice.Remainder = invoice.Remainder.Sub(m.Amount)
+	}
+	report.OpenInvoices = []Invoice{}
+	for _, invoice := range open {
+		if invoice.Remainder.Sign() > 0 {
+			report.OpenInvoices = append(report.OpenInvoices, *invoice)
+		}
+	}
+	return report
+}
+
+func matchPayment(payment JournalEntry, paid Amount, open []*Invoice, byNumber map[int]*Invoice, eligible func(*Invoice) bool, length int, opts ReconcileOptions, m *PaymentMatch) (*Invoice, string) {
+	// one invoice named in the text wins; several named is ambiguous
+	pick := func(candidates []*Invoice, by string) (*Invoice, string, bool) {
+		unique := map[int]*Invoice{}
+		for _, invoice := range candidates {
+			if eligible(invoice) {
+				unique[invoice.BookedInvoiceNumber] = invoice
+			}
+		}
+		switch len(unique) {
+		case 0:
+			return nil, "", false
+		case 1:
+			for _, invoice := range unique {
+				m.MatchedBy = by
+				return invoice, "", true
+			}
+		}
+		return nil, fmt.Sprintf("%s fits %d invoices", by, len(unique)), true
+	}
+	var candidates []*Invoice
+	for _, n := range paymentIdInvoices(payment.Text, length) {
+		candidates = append(candidates, byNumber[n])
+	}
+	if invoice, reason, ok := pick(candidates, MatchedByPaymentId); ok {
+		return invoice, reason
+	}
+	candidates = nil
+	for _, invoice := range open {
+		if invoice.References != nil && invoice.References.Other != "" && containsWord(payment.Text, invoice.References.Other) {
+			candidates = append(candidates, invoice)
+		}
+	}
+	if invoice, reason, ok := pick(candidates, MatchedByReference); ok {
+		return invoice, reason
+	}
+	candidates = nil
+	for _, match := range invoiceNumberInText.FindAllStringSubmatch(payment.Text, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil {
+			candidates = append(candidates, byNumber[n])
+		}
+	}
+	if invoice, reason, ok := pick(candidates, MatchedByInvoiceNumber); ok {
+		return invoice, reason
+	}
+	if opts.NoAmountMatch {
+		return nil, "no invoice named in the text"
+	}
+	candidates = nil
+	for _, invoice := range open {
+		if invoice.Remainder == paid {
+			candidates = append(candidates, invoice)
+		}
+	}
+	if invoice, reason, ok := pick(candidates, MatchedByAmount); ok {
+		return invoice, reason
+	}
+	return nil, "no open invoice fits"
+}
+
+func (client *Client) ReconcilePayments(payments []JournalEntry, opts ReconcileOptions) (*ReconcileReport, error) {
+	return client.ReconcilePaymentsContext(context.Background(), payments, opts)
+}
+
+// ReconcilePaymentsContext reconciles payments with the unpaid booked
+// invoices; see Reconcile.
+func (client *Client) ReconcilePaymentsContext(ctx context.Context, payments []JournalEntry, opts ReconcileOptions) (*ReconcileReport, error) {
+	tc := &TypedClient[Invoice]{client: client}
+	invoices, err := tc.getEntities(ctx, "invoices/unpaid", ListOptions{PageSize: invoicePageSize})
+	if err != nil {
+		return nil, err
+	}
+	return Reconcile(invoices, payments, opts), nil
+}
+
+// SettleOptions configures SettleMatches.
+type SettleOptions struct {
+	JournalNumber   int
+	EntryTypeNumber int // The entry type of customer payments.
+	// ContraAccountNumber is the account the payments were received on:
+	// the bank account, or the contra account a bank import posted them to.
+	// A payment already debited to it under the same voucher is not settled
+	// again, as that would book it twice.
+	ContraAccountNumber int
+}
+
+// SettleResult is the outcome of settling one match.
+type SettleResult struct {
+	Match     PaymentMatch
+	Entry     JournalEntry // The customer payment draft entry.
+	Created   bool
+	Duplicate bool // The entry already exists under the payment's voucher number.
+	Err       error
+}
+
+func (client *Client) SettleMatches(report *ReconcileReport, opts SettleOptions) ([]SettleResult, error) {
+	return client.SettleMatchesContext(context.Background(), report, opts)
+}
+
+// SettleMatchesContext creates a customer payment draft entry for each
+// matched, partial and overpaid payment in report, settling Amount of the
+// invoice. Overpayments settle what was left of the invoice; the rest stays
+// on the contra account. Entries reuse the voucher number of the payment,
+// and are skipped as duplicates if the voucher already has a payment of the
+// same amount for the invoice. The error is only set for invalid options.
+func (client *Client) SettleMatchesContext(ctx context.Context, report *ReconcileReport, opts SettleOptions) ([]SettleResult, error) {
+	if opts.JournalNumber == 0 || opts.ContraAccountNumber == 0 {
+		return nil, fmt.Errorf("settling payments needs a journal number and a contra account number")
+	}
+	results := []SettleResult{}
+	for _, m := range report.Matches {
+		if m.Invoice == nil || m.Status == ReconcileUnmatched || m.Amount.IsZero() {
+			continue
+		}
+		result := SettleResult{Match: m, Entry: JournalEntry{
+			EntryTypeNumber:     opts.EntryTypeNumber,
+			VoucherNumber:       m.Payment.VoucherNumber,
+			JournalNumber:       opts.JournalNumber,
+			Date:                m.Payment.Date,
+			Amount:              m.Amount.Neg(), // credits the customer
+			Currency:            m.Invoice.Currency,
+			ContraAccountNumber: opts.ContraAccountNumber,
+			CustomerNumber:      invoiceCustomerNumber(m.Invoice),
+			InvoiceNumber:       m.Invoice.BookedInvoiceNumber,
+			Text:                fmt.Sprintf("Payment of invoice %d", m.Invoice.BookedInvoiceNumber),
+		}}
+		client.settleMatch(ctx, &result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (client *Client) settleMatch(ctx context.Context, result *SettleResult) {
+	entry := &result.Entry
+	if entry.VoucherNumber != 0 {
+		existing, err := client.GetAllJournalEntriesByVoucherNumberContext(ctx, entry.VoucherNumber)
+		if err != nil {
+			result.Err = err
+			return
+		}
+		for _, e := range existing {
+			if e.InvoiceNumber == entry.InvoiceNumber && e.Amount == entry.Amount {
+				result.Duplicate = true
+				return
+			}
+		}
+		for _, e := range existing {
+			debited := (e.AccountNumber == entry.ContraAccountNumber && e.Amount.Sign() > 0) ||
+				(e.ContraAccountNumber == entry.ContraAccountNumber && e.Amount.Sign() < 0)
+			if e.InvoiceNumber == 0 && debited {
+				result.Err = fmt.Errorf("voucher %d already debits account %d with the payment; settle against the account it was credited to",
+					entry.VoucherNumber, entry.ContraAccountNumber)
+				return
+			}
+		}
+	}
+	if result.Err = client.CreateJournalEntryContext(ctx, entry); result.Err == nil {
+		result.Created = true
+	}
+}
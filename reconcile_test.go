@@ -0,0 +1,199 @@
+package economic
+
+import (
+	"testing"
+)
+
+func testOpenInvoice(number, customer int, remainder, ref string) Invoice {
+	invoice := Invoice{
+		BookedInvoiceNumber: number,
+		Date:                NewDate(2024, 3, 1),
+		Currency:            "DKK",
+		GrossAmount:         MustParseAmount(remainder),
+		Remainder:           MustParseAmount(remainder),
+		Customer:            &Customer{CustomerNumber: customer},
+	}
+	if ref != "" {
+		invoice.References = &References{Other: ref}
+	}
+	return invoice
+}
+
+func testPayment(voucher int, amount, text string) JournalEntry {
+	return JournalEntry{JournalNumber: 1, VoucherNumber: voucher, Date: NewDate(2024, 3, 10), Amount: MustParseAmount(amount), Currency: "DKK", Text: text}
+}
+
+func TestFikPaymentId(t *testing.T) {
+	id := FikPaymentId(1042, 15)
+	if len(id) != 15 || id[:14] != "00000000001042" {
+		t.Fatalf("Unexpected payment id %s", id)
+	}
+	if numbers := paymentIdInvoices("+71<"+id+"+12345678<", 15); len(numbers) != 1 || numbers[0] != 1042 {
+		t.Fatalf("Expected invoice 1042, got %v", numbers)
+	}
+	wrong := id[:14] + string('0'+(id[14]-'0'+1)%10)
+	if numbers := paymentIdInvoices(wrong, 15); len(numbers) != 0 {
+		t.Fatalf("Expected a wrong check digit to be rejected, got %v", numbers)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	invoices := []Invoice{
+		testOpenInvoice(1001, 1, "1250", ""),
+		testOpenInvoice(1002, 2, "500", "ORD-17"),
+		testOpenInvoice(1003, 3, "800", ""),
+		testOpenInvoice(1004, 4, "300", ""),
+		testOpenInvoice(1005, 5, "300", ""),
+		testOpenInvoice(1006, 6, "99", ""),
+		{BookedInvoiceNumber: 1007, Currency: "DKK", Remainder: MustParseAmount("-100")},
+	}
+	payments := []JournalEntry{
+		testPayment(1, "1250", "Indbetaling +71<"+FikPaymentId(1001, 15)+"+"),
+		testPayment(2, "200", "Betaling ord-17"),
+		testPayment(3, "800", "Faktura nr. 1003"),
+		testPayment(4, "300", "Ukendt"),
+		testPayment(5, "120", "Overført faktura 1006"),
+		testPayment(6, "300", "ORD-170"),
+		testPayment(7, "100", "Kreditnota"),
+	}
+	payments[2].CustomerNumber = 3
+	report := Reconcile(invoices, payments, ReconcileOptions{})
+
+	expect := []struct {
+		status    ReconcileStatus
+		by        string
+		invoice   int
+		amount    string
+		remainder string
+	}{
+		{ReconcileMatched, MatchedByPaymentId, 1001, "1250", "1250"},
+		{ReconcilePartial, MatchedByReference, 1002, "200", "500"},
+		{ReconcileMatched, MatchedByInvoiceNumber, 1003, "800", "800"},
+		{ReconcileUnmatched, "", 0, "0", ""}, // two invoices of 300
+		{ReconcileOverpaid, MatchedByInvoiceNumber, 1006, "99", "99"},
+		{ReconcileUnmatched, "", 0, "0", ""}, // ORD-170 is not ORD-17, and the amount is ambiguous
+		{ReconcileUnmatched, "", 0, "0", ""}, // credit notes are not open
+	}
+	for i, e := range expect {
+		m := report.Matches[i]
+		if m.Status != e.status || m.MatchedBy != e.by || m.Amount != MustParseAmount(e.amount) {
+			t.Fatalf("Payment %d: expected %s by %q of %s, got %s by %q of %s (%s)", i+1, e.status, e.by, e.amount, m.Status, m.MatchedBy, m.Amount, m.Reason)
+		}
+		if e.invoice == 0 {
+			if m.Invoice != nil || m.Reason == "" {
+				t.Fatalf("Payment %d: expected no invoice and a reason, got %+v", i+1, m)
+			}
+			continue
+		}
+		if m.Invoice == nil || m.Invoice.BookedInvoiceNumber != e.invoice || m.Invoice.Remainder != MustParseAmount(e.remainder) {
+			t.Fatalf("Payment %d: expected invoice %d, got %+v", i+1, e.invoice, m.Invoice)
+		}
+	}
+	if report.Matches[4].Difference != MustParseAmount("21") {
+		t.Fatalf("Expected an overpayment of 21, got %s", report.Matches[4].Difference)
+	}
+	if len(report.Matched()) != 2 || len(report.Partial()) != 1 || len(report.Overpaid()) != 1 || len(report.Unmatched()) != 3 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	open := map[int]Amount{}
+	for _, invoice := range report.OpenInvoices {
+		open[invoice.BookedInvoiceNumber] = invoice.Remainder
+	}
+	if len(open) != 3 || open[1002] != MustParseAmount("300") || open[1004].IsZero() || open[1005].IsZero() {
+		t.Fatalf("Unexpected open invoices %v", open)
+	}
+
+	// a payment only matches its own customer's invoices
+	payments[2].CustomerNumber = 9
+	if m := Reconcile(invoices, payments, ReconcileOptions{}).Matches[2]; m.Status != ReconcileUnmatched {
+		t.Fatalf("Expected no match for another customer, got %+v", m)
+	}
+	// outgoing payments and refunds match nothing, unless incoming payments
+	// are negative
+	refund := []JournalEntry{testPayment(9, "-1250", "Faktura 1001")}
+	if m := Reconcile(invoices, refund, ReconcileOptions{}).Matches[0]; m.Status != ReconcileUnmatched || m.Invoice != nil {
+		t.Fatalf("Expected a refund to be unmatched, got %+v", m)
+	}
+	if m := Reconcile(invoices, refund, ReconcileOptions{IncomingNegative: true}).Matches[0]; m.Status != ReconcileMatched || m.Amount != MustParseAmount("1250") {
+		t.Fatalf("Expected a match with negative incoming payments, got %+v", m)
+	}
+	refund[0].Amount = MustParseAmount("800")
+	if m := Reconcile(invoices, refund, ReconcileOptions{IncomingNegative: true}).Matches[0]; m.Status != ReconcileUnmatched {
+		t.Fatalf("Expected a positive payment to be outgoing, got %+v", m)
+	}
+	// without amount matching, a text naming no invoice is unmatched
+	only := []JournalEntry{testPayment(8, "99", "Ukendt")}
+	if m := Reconcile(invoices, only, ReconcileOptions{NoAmountMatch: true}).Matches[0]; m.Status != ReconcileUnmatched {
+		t.Fatalf("Expected no amount match, got %+v", m)
+	}
+}
+
+func TestReconcileAndSettle(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.Add("invoices/booked", testOpenInvoice(1001, 1, "1250", ""), testOpenInvoice(1002, 2, "500", "ORD-17"))
+	srv.Update("invoices/booked", 1002, func(item map[string]any) { item["remainder"] = 0.0 })
+	payments := []JournalEntry{
+		testPayment(101, "1250", "Faktura 1001"),
+		testPayment(102, "500", "ORD-17"),
+	}
+	report, err := client.ReconcilePayments(payments, ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if report.Matches[0].Status != ReconcileMatched || report.Matches[1].Status != ReconcileUnmatched {
+		t.Fatalf("Expected only the unpaid invoice to match, got %+v", report.Matches)
+	}
+	opts := SettleOptions{JournalNumber: 1, EntryTypeNumber: 3, ContraAccountNumber: 9990}
+	results, err := client.SettleMatches(report, opts)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(results) != 1 || !results[0].Created || results[0].Err != nil {
+		t.Fatalf("Expected one payment entry, got %+v", results)
+	}
+	entry := results[0].Entry
+	if entry.Amount != MustParseAmount("-1250") || entry.CustomerNumber != 1 || entry.InvoiceNumber != 1001 ||
+		entry.VoucherNumber != 101 || entry.ContraAccountNumber != 9990 {
+		t.Fatalf("Unexpected payment entry %+v", entry)
+	}
+	results, err = client.SettleMatches(report, opts)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(results) != 1 || !results[0].Duplicate || results[0].Created {
+		t.Fatalf("Expected the second settlement to be a duplicate, got %+v", results)
+	}
+
+	// a payment a bank import already debited to the bank account is only
+	// settled against the import's contra account
+	srv.Add("invoices/booked", testOpenInvoice(1003, 3, "300", ""))
+	imported := JournalEntry{JournalNumber: 1, VoucherNumber: 103, Date: NewDate(2024, 3, 10), Amount: MustParseAmount("300"), Currency: "DKK",
+		Text: "Faktura 1003", AccountNumber: 5820, ContraAccountNumber: 9990}
+	if err := client.CreateJournalEntry(&imported); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	report, err = client.ReconcilePayments([]JournalEntry{imported}, ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	results, err = client.SettleMatches(report, SettleOptions{JournalNumber: 1, ContraAccountNumber: 5820})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(results) != 1 || results[0].Err == nil || results[0].Created {
+		t.Fatalf("Expected settling against the bank account to fail, got %+v", results)
+	}
+	results, err = client.SettleMatches(report, opts)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(results) != 1 || !results[0].Created || results[0].Err != nil {
+		t.Fatalf("Expected the payment to be settled, got %+v", results)
+	}
+	if n := len(srv.Items("draft-entries")); n != 3 {
+		t.Fatalf("Expected 3 draft entries, got %d", n)
+	}
+	if _, err := client.SettleMatches(report, SettleOptions{JournalNumber: 1}); err == nil {
+		t.Fatalf("Expected an error without a contra account")
+	}
+}
@@ -0,0 +1,338 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+func (client *Client) GetLayouts() ([]Layout, error) {
+	return client.GetLayoutsContext(context.Background())
+}
+
+func (client *Client) GetLayoutsContext(ctx context.Context) ([]Layout, error) {
+	tc := &TypedClient[Layout]{client: client}
+	return tc.getEntities(ctx, "layouts", ListOptions{})
+}
+
+func (client *Client) GetLayout(number int) (*Layout, error) {
+	return client.GetLayoutContext(context.Background(), number)
+}
+
+func (client *Client) GetLayoutContext(ctx context.Context, number int) (*Layout, error) {
+	var layout Layout
+	err := client.callRestAPI(ctx, fmt.Sprintf("layouts/%d", number), http.MethodGet, nil, &layout)
+	return &layout, err
+}
+
+func (client *Client) GetVatZones() ([]VatZone, error) {
+	return client.GetVatZonesContext(context.Background())
+}
+
+func (client *Client) GetVatZonesContext(ctx context.Context) ([]VatZone, error) {
+	tc := &TypedClient[VatZone]{client: client}
+	return tc.getEntities(ctx, "vat-zones", ListOptions{})
+}
+
+func (client *Client) GetVatZone(number int) (*VatZone, error) {
+	return client.GetVatZoneContext(context.Background(), number)
+}
+
+func (client *Client) GetVatZoneContext(ctx context.Context, number int) (*VatZone, error) {
+	var zone VatZone
+	err := client.callRestAPI(ctx, fmt.Sprintf("vat-zones/%d", number), http.MethodGet, nil, &zone)
+	return &zone, err
+}
+
+func (client *Client) GetUnits() ([]Unit, error) {
+	return client.GetUnitsContext(context.Background())
+}
+
+func (client *Client) GetUnitsContext(ctx context.Context) ([]Unit, error) {
+	tc := &TypedClient[Unit]{client: client}
+	return tc.getEntities(ctx, "units", ListOptions{})
+}
+
+func (client *Client) GetUnit(number int) (*Unit, error) {
+	return client.GetUnitContext(context.Background(), number)
+}
+
+func (client *Client) GetUnitContext(ctx context.Context, number int) (*Unit, error) {
+	var unit Unit
+	err := client.callRestAPI(ctx, fmt.Sprintf("units/%d", number), http.MethodGet, nil, &unit)
+	return &unit, err
+}
+
+func (client *Client) GetCurrencies() ([]Currency, error) {
+	return client.GetCurrenciesContext(context.Background())
+}
+
+func (client *Client) GetCurrenciesContext(ctx context.Context) ([]Currency, error) {
+	tc := &TypedClient[Currency]{client: client}
+	return tc.getEntities(ctx, "currencies", ListOptions{})
+}
+
+func (client *Client) GetCurrency(code string) (*Currency, error) {
+	return client.GetCurrencyContext(context.Background(), code)
+}
+
+func (client *Client) GetCurrencyContext(ctx context.Context, code string) (*Currency, error) {
+	var currency Currency
+	err := client.callRestAPI(ctx, "currencies/"+url.PathEscape(strings.ToUpper(code)), http.MethodGet, nil, &currency)
+	return &currency, err
+}
+
+func (client *Client) GetCustomerGroups() ([]CustomerGroup, error) {
+	return client.GetCustomerGroupsContext(context.Background())
+}
+
+func (client *Client) GetCustomerGroupsContext(ctx context.Context) ([]CustomerGroup, error) {
+	tc := &TypedClient[CustomerGroup]{client: client}
+	return tc.getEntities(ctx, "customer-groups", ListOptions{})
+}
+
+func (client *Client) GetCustomerGroup(number int) (*CustomerGroup, error) {
+	return client.GetCustomerGroupContext(context.Background(), number)
+}
+
+func (client *Client) GetCustomerGroupContext(ctx context.Context, number int) (*CustomerGroup, error) {
+	var group CustomerGroup
+	err := client.callRestAPI(ctx, fmt.Sprintf("customer-groups/%d", number), http.MethodGet, nil, &group)
+	return &group, err
+}
+
+// ReferenceData is a snapshot of the agreement's layouts, VAT zones, units,
+// currencies, customer groups and payment terms, used to resolve names to
+// numbers and to validate references before creating customers and orders.
+type ReferenceData struct {
+	Layouts        []Layout
+	VatZones       []VatZone
+	Units          []Unit
+	Currencies     []Currency
+	CustomerGroups []CustomerGroup
+	PaymentTerms   []PaymentTerm
+	LoadedAt       time.Time
+}
+
+func find[T any](items []T, match func(T) bool) (T, bool) {
+	for _, item := range items {
+		if match(item) {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (r *ReferenceData) Layout(number int) (Layout, bool) {
+	return find(r.Layouts, func(l Layout) bool { return l.LayoutNumber == number })
+}
+
+// LayoutByName finds a layout by its name, ignoring case.
+func (r *ReferenceData) LayoutByName(name string) (Layout, bool) {
+	return find(r.Layouts, func(l Layout) bool { return strings.EqualFold(l.Name, name) })
+}
+
+func (r *ReferenceData) VatZone(number int) (VatZone, bool) {
+	return find(r.VatZones, func(z VatZone) bool { return z.VatZoneNumber == number })
+}
+
+// VatZoneByName finds a VAT zone by its name, e.g. "Domestic", ignoring case.
+func (r *ReferenceData) VatZoneByName(name string) (VatZone, bool) {
+	return find(r.VatZones, func(z VatZone) bool { return strings.EqualFold(z.Name, name) })
+}
+
+func (r *ReferenceData) Unit(number int) (Unit, bool) {
+	return find(r.Units, func(u Unit) bool { return u.UnitNumber == number })
+}
+
+// UnitByName finds a unit by its name, e.g. "stk.", ignoring case.
+func (r *ReferenceData) UnitByName(name string) (Unit, bool) {
+	return find(r.Units, func(u Unit) bool { return strings.EqualFold(u.Name, name) })
+}
+
+// Currency finds a currency by its ISO 4217 code, ignoring case.
+func (r *ReferenceData) Currency(code string) (Currency, bool) {
+	return find(r.Currencies, func(c Currency) bool { return strings.EqualFold(c.Code, code) })
+}
+
+func (r *ReferenceData) CustomerGroup(number int) (CustomerGroup, bool) {
+	return find(r.CustomerGroups, func(g CustomerGroup) bool { return g.CustomerGroupNumber == number })
+}
+
+// CustomerGroupByName finds a customer group by its name, ignoring case.
+func (r *ReferenceData) CustomerGroupByName(name string) (CustomerGroup, bool) {
+	return find(r.CustomerGroups, func(g CustomerGroup) bool { return strings.EqualFold(g.Name, name) })
+}
+
+func (r *ReferenceData) PaymentTerm(number int) (PaymentTerm, bool) {
+	return find(r.PaymentTerms, func(p PaymentTerm) bool { return p.PaymentTermsNumber == number })
+}
+
+// PaymentTermByName finds payment terms by their name, ignoring case.
+func (r *ReferenceData) PaymentTermByName(name string) (PaymentTerm, bool) {
+	return find(r.PaymentTerms, func(p PaymentTerm) bool { return strings.EqualFold(p.Name, name) })
+}
+
+// ValidateCustomer checks that the VAT zone, customer group, payment terms,
+// currency and layout (if any) of customer exist.
+func (r *ReferenceData) ValidateCustomer(customer *Customer) error {
+	var errs []error
+	if _, ok := r.VatZone(customer.VatZone.VatZoneNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown VAT zone %d", customer.VatZone.VatZoneNumber))
+	}
+	if _, ok := r.CustomerGroup(customer.CustomerGroup.CustomerGroupNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown customer group %d", customer.CustomerGroup.CustomerGroupNumber))
+	}
+	if _, ok := r.PaymentTerm(customer.PaymentTerms.PaymentTermsNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown payment terms %d", customer.PaymentTerms.PaymentTermsNumber))
+	}
+	if _, ok := r.Currency(customer.Currency); !ok {
+		errs = append(errs, fmt.Errorf("unknown currency %q", customer.Currency))
+	}
+	if customer.Layout != nil {
+		if _, ok := r.Layout(customer.Layout.LayoutNumber); !ok {
+			errs = append(errs, fmt.Errorf("unknown layout %d", customer.Layout.LayoutNumber))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateOrder checks that the layout, payment terms, currency, recipient
+// VAT zone and line units of order exist.
+func (r *ReferenceData) ValidateOrder(order *Order) error {
+	var errs []error
+	if _, ok := r.Layout(order.Layout.LayoutNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown layout %d", order.Layout.LayoutNumber))
+	}
+	if _, ok := r.PaymentTerm(order.PaymentTerms.PaymentTermsNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown payment terms %d", order.PaymentTerms.PaymentTermsNumber))
+	}
+	if _, ok := r.Currency(order.Currency); !ok {
+		errs = append(errs, fmt.Errorf("unknown currency %q", order.Currency))
+	}
+	if _, ok := r.VatZone(order.Recipient.VatZone.VatZoneNumber); !ok {
+		errs = append(errs, fmt.Errorf("unknown recipient VAT zone %d", order.Recipient.VatZone.VatZoneNumber))
+	}
+	for _, line := range order.Lines {
+		if line.Unit == nil {
+			continue
+		}
+		if _, ok := r.Unit(line.Unit.UnitNumber); !ok {
+			errs = append(errs, fmt.Errorf("line %d: unknown unit %d", line.LineNumber, line.Unit.UnitNumber))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Registry caches the reference data of an agreement. Reference data rarely
+// changes, so a Client loads it once and reuses it for TTL.
+type Registry struct {
+	TTL time.Duration // how long a snapshot is used; zero keeps it until Refresh
+
+	mu   sync.Mutex
+	data *ReferenceData
+}
+
+// registryInit guards the lazy creation of Client.Registry.
+var registryInit sync.Mutex
+
+func (client *Client) registry() *Registry {
+	registryInit.Lock()
+	defer registryInit.Unlock()
+	if client.Registry == nil {
+		client.Registry = &Registry{}
+	}
+	return client.Registry
+}
+
+// ReferenceData returns the cached reference data, loading it on first use
+// and after the registry's TTL.
+func (client *Client) ReferenceData() (*ReferenceData, error) {
+	return client.ReferenceDataContext(context.Background())
+}
+
+func (client *Client) ReferenceDataContext(ctx context.Context) (*ReferenceData, error) {
+	r := client.registry()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data != nil && (r.TTL == 0 || time.Since(r.data.LoadedAt) < r.TTL) {
+		return r.data, nil
+	}
+	data, err := client.loadReferenceData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.data = data
+	return data, nil
+}
+
+// RefreshReferenceData reloads the reference data, e.g. after adding a layout.
+func (client *Client) RefreshReferenceData() (*ReferenceData, error) {
+	return client.RefreshReferenceDataContext(context.Background())
+}
+
+func (client *Client) RefreshReferenceDataContext(ctx context.Context) (*ReferenceData, error) {
+	r := client.registry()
+	r.mu.Lock()
+	r.data = nil
+	r.mu.Unlock()
+	return client.ReferenceDataContext(ctx)
+}
+
+func (client *Client) loadReferenceData(ctx context.Context) (*ReferenceData, error) {
+	data := &ReferenceData{LoadedAt: time.Now()}
+	var err error
+	if data.Layouts, err = client.GetLayoutsContext(ctx); err != nil {
+		return nil, err
+	}
+	if data.VatZones, err = client.GetVatZonesContext(ctx); err != nil {
+		return nil, err
+	}
+	if data.Units, err = client.GetUnitsContext(ctx); err != nil {
+		return nil, err
+	}
+	if data.Currencies, err = client.GetCurrenciesContext(ctx); err != nil {
+		return nil, err
+	}
+	if data.CustomerGroups, err = client.GetCustomerGroupsContext(ctx); err != nil {
+		return nil, err
+	}
+	if data.PaymentTerms, err = client.GetPaymentTermsContext(ctx); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ValidateCustomerReferences checks the references of customer against the
+// cached reference data.
+func (client *Client) ValidateCustomerReferences(customer *Customer) error {
+	return client.ValidateCustomerReferencesContext(context.Background(), customer)
+}
+
+func (client *Client) ValidateCustomerReferencesContext(ctx context.Context, customer *Customer) error {
+	data, err := client.ReferenceDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	return data.ValidateCustomer(customer)
+}
+
+// ValidateOrderReferences checks the references of order against the cached
+// reference data.
+func (client *Client) ValidateOrderReferences(order *Order) error {
+	return client.ValidateOrderReferencesContext(context.Background(), order)
+}
+
+func (client *Client) ValidateOrderReferencesContext(ctx context.Context, order *Order) error {
+	data, err := client.ReferenceDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	return data.ValidateOrder(order)
+}
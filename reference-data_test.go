@@ -0,0 +1,83 @@
+package economic
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReferenceData(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	data, err := client.ReferenceData()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if zone, ok := data.VatZoneByName("eu"); !ok || zone.VatZoneNumber != 2 {
+		t.Fatalf("Expected VAT zone 2, got %+v", zone)
+	}
+	if unit, ok := data.UnitByName("Timer"); !ok || unit.UnitNumber != 2 {
+		t.Fatalf("Expected unit 2, got %+v", unit)
+	}
+	if _, ok := data.Currency("dkk"); !ok {
+		t.Fatalf("Expected DKK")
+	}
+	if terms, ok := data.PaymentTermByName("netto 8 dage"); !ok || terms.PaymentTermsNumber != 10 {
+		t.Fatalf("Expected payment terms 10, got %+v", terms)
+	}
+	if _, ok := data.LayoutByName("missing"); ok {
+		t.Fatalf("Expected no layout named missing")
+	}
+
+	if _, err := client.ReferenceData(); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if n := countRequests(srv, http.MethodGet, "vat-zones"); n != 1 {
+		t.Fatalf("Expected reference data to be cached, got %d requests", n)
+	}
+	srv.Add("layouts", Layout{LayoutNumber: 20, Name: "Engelsk"})
+	for i := 0; i < DEFAULT_PAGE_SIZE; i++ {
+		srv.Add("payment-terms", PaymentTerm{PaymentTermsNumber: 100 + i, Name: fmt.Sprintf("Netto %d dage", 100+i), PaymentTermsType: "net"})
+	}
+	data, err = client.RefreshReferenceData()
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, ok := data.LayoutByName("engelsk"); !ok {
+		t.Fatalf("Expected the new layout after a refresh")
+	}
+	if _, ok := data.PaymentTerm(100 + DEFAULT_PAGE_SIZE - 1); !ok {
+		t.Fatalf("Expected payment terms beyond the first page, got %d", len(data.PaymentTerms))
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	client := getTestClient(t)
+	order := testOrder("")
+	order.Lines = []OrderLine{{LineNumber: 1, Unit: &Unit{UnitNumber: 1}}}
+	if err := client.ValidateOrderReferences(order); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	order.Layout.LayoutNumber = 99
+	order.Lines[0].Unit.UnitNumber = 42
+	err := client.ValidateOrderReferences(order)
+	if err == nil || !strings.Contains(err.Error(), "layout 99") || !strings.Contains(err.Error(), "unit 42") {
+		t.Fatalf("Expected unknown layout and unit, got %v", err)
+	}
+
+	customer := &Customer{
+		Name:          "Reference ApS",
+		Currency:      "SEK",
+		VatZone:       VatZone{VatZoneNumber: 1},
+		CustomerGroup: CustomerGroup{CustomerGroupNumber: 1},
+		PaymentTerms:  PaymentTerms{PaymentTermsNumber: 10},
+	}
+	err = client.ValidateCustomerReferences(customer)
+	if err == nil || !strings.Contains(err.Error(), `"SEK"`) {
+		t.Fatalf("Expected unknown currency, got %v", err)
+	}
+	customer.Currency = "DKK"
+	if err := client.ValidateCustomerReferences(customer); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+}
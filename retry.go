@@ -0,0 +1,83 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RetryPolicy decides which failed requests are sent again.
+type RetryPolicy int
+
+const (
+	// RetrySafe re-sends idempotent requests (GET, PUT, DELETE) after a
+	// network error, a 429 or a 5xx. Writes (POST, PATCH) are re-sent after a
+	// 429, which e-conomic returns without processing the request; after a
+	// network error or 5xx they are only re-sent when the call can look up
+	// whether the first attempt took effect, and it did not.
+	RetrySafe RetryPolicy = iota
+	// RetryAlways re-sends every failed request, writes included.
+	RetryAlways
+	// RetryNever sends every request once.
+	RetryNever
+)
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy overrides the client's RetryPolicy for calls made with the
+// returned context, e.g.
+//
+//	client.BookInvoiceContext(economic.WithRetryPolicy(ctx, economic.RetryNever), n)
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+func (client *Client) retryPolicy(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return client.RetryPolicy
+}
+
+// lookupFunc checks whether a write whose outcome is unknown took effect
+// anyway. If it did, it fills in the call's response and returns true.
+type lookupFunc func(ctx context.Context) (found bool, err error)
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// needsLookup reports whether a failed attempt with status (0 for a network
+// error) may have taken effect, so that it must be looked up before it is
+// sent again.
+func (client *Client) needsLookup(ctx context.Context, method string, status int) bool {
+	return client.retryPolicy(ctx) == RetrySafe && !isIdempotent(method) && status != http.StatusTooManyRequests
+}
+
+// mayRetry reports whether a failed attempt may be sent again.
+func (client *Client) mayRetry(ctx context.Context, method string, status int, lookup lookupFunc) bool {
+	switch client.retryPolicy(ctx) {
+	case RetryNever:
+		return false
+	case RetryAlways:
+		return true
+	}
+	return !client.needsLookup(ctx, method, status) || lookup != nil
+}
+
+// beforeResend runs the lookup when needed. It returns true if the previous
+// attempt took effect, and an error wrapping lastErr if that is unknown.
+func (client *Client) beforeResend(ctx context.Context, method string, status int, lastErr error, lookup lookupFunc) (bool, error) {
+	if lookup == nil || !client.needsLookup(ctx, method, status) {
+		return false, nil
+	}
+	found, err := lookup(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%w (not retried, checking whether it took effect failed: %v)", lastErr, err)
+	}
+	return found, nil
+}
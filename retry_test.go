@@ -0,0 +1,198 @@
+package economic
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Opus-EDB/e-conomic/econtest"
+)
+
+func testOrder(ref string) *Order {
+	order := &Order{
+		Date:         MustParseDate("2024-03-01"),
+		Currency:     "DKK",
+		Layout:       Layout{LayoutNumber: 19},
+		PaymentTerms: PaymentTerms{PaymentTermsNumber: 10},
+		Customer:     CustomerID{CustomerNumber: 1},
+		Recipient:    Recipient{Name: "Retry A/S", VatZone: VatZone{VatZoneNumber: 1}},
+		Lines:        []OrderLine{},
+	}
+	if ref != "" {
+		order.References = &References{Other: ref}
+	}
+	return order
+}
+
+func countRequests(srv *econtest.Server, method, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCreateInvoiceLooksUpBeforeRetrying(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.LoseNextResponse(http.MethodPost, "invoices/drafts", http.StatusServiceUnavailable)
+	invoice, err := client.CreateInvoice(testOrder("retry-1"))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if invoice.DraftInvoiceNumber == 0 {
+		t.Fatalf("Expected the looked up draft, got %+v", invoice)
+	}
+	if drafts := srv.Items("invoices/drafts"); len(drafts) != 1 {
+		t.Fatalf("Expected a single draft, got %d", len(drafts))
+	}
+	if posts := countRequests(srv, http.MethodPost, "invoices/drafts"); posts != 1 {
+		t.Fatalf("Expected the invoice to be posted once, got %d", posts)
+	}
+}
+
+func TestCreateInvoiceRetriesWhenLookupFindsNothing(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.FailNext(http.MethodPost, "invoices/drafts", http.StatusServiceUnavailable)
+	if _, err := client.CreateInvoice(testOrder("retry-2")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if drafts := srv.Items("invoices/drafts"); len(drafts) != 1 {
+		t.Fatalf("Expected a single draft, got %d", len(drafts))
+	}
+	if posts := countRequests(srv, http.MethodPost, "invoices/drafts"); posts != 2 {
+		t.Fatalf("Expected the invoice to be posted again, got %d posts", posts)
+	}
+}
+
+func TestWritesWithoutLookupAreNotRetried(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.FailNext(http.MethodPost, "invoices/drafts", http.StatusServiceUnavailable)
+	_, err := client.CreateInvoice(testOrder(""))
+	if apiErr, ok := AsAPIError(err); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the 503, got %v", err)
+	}
+	if posts := countRequests(srv, http.MethodPost, "invoices/drafts"); posts != 1 {
+		t.Fatalf("Expected a single post, got %d", posts)
+	}
+
+	// a 429 means the request was not processed, so it is safe to send again
+	srv.FailNext(http.MethodPost, "invoices/drafts", http.StatusTooManyRequests)
+	if _, err := client.CreateInvoice(testOrder("")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+
+	// and the policy can be overridden per call
+	srv.FailNext(http.MethodPost, "invoices/drafts", http.StatusServiceUnavailable)
+	if _, err := client.CreateInvoiceContext(WithRetryPolicy(context.Background(), RetryAlways), testOrder("")); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	srv.FailNext(http.MethodGet, "invoices/drafts", http.StatusServiceUnavailable)
+	if _, err := client.GetDraftInvoicesContext(WithRetryPolicy(context.Background(), RetryNever), 10); err == nil {
+		t.Fatalf("Expected RetryNever to return the first failure")
+	}
+}
+
+func TestBookInvoiceIsNotBookedTwice(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	draft, err := client.CreateInvoice(testOrder("book-1"))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	srv.LoseNextResponse(http.MethodPost, "invoices/booked", http.StatusBadGateway)
+	if _, err := client.BookInvoice(draft.DraftInvoiceNumber); err == nil {
+		t.Fatalf("Expected an error when the booked invoice cannot be identified")
+	}
+	if booked := srv.Items("invoices/booked"); len(booked) != 1 {
+		t.Fatalf("Expected a single booked invoice, got %d", len(booked))
+	}
+
+	draft, err = client.CreateInvoice(testOrder("book-2"))
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	number := 5000
+	srv.LoseNextResponse(http.MethodPost, "invoices/booked", http.StatusBadGateway)
+	invoice, err := client.BookInvoice(draft.DraftInvoiceNumber, BookInvoiceOptions{BookWithNumber: &number})
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if invoice.BookedInvoiceNumber != number {
+		t.Fatalf("Expected booked invoice %d, got %d", number, invoice.BookedInvoiceNumber)
+	}
+}
+
+func TestCreateJournalEntryLooksUpByVoucher(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.LoseNextResponse(http.MethodPost, "journalsapi", http.StatusGatewayTimeout)
+	entry := &JournalEntry{JournalNumber: 1, VoucherNumber: 42, Date: MustParseDate("2024-03-01"), Amount: MustParseAmount("100.00"), AccountNumber: 5820, Currency: "DKK", Text: "retry"}
+	if err := client.CreateJournalEntry(entry); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if entry.EntryNumber == 0 {
+		t.Fatalf("Expected the entry number of the looked up entry")
+	}
+	if entries := srv.Items("draft-entries"); len(entries) != 1 {
+		t.Fatalf("Expected a single draft entry, got %d", len(entries))
+	}
+}
+
+func TestCreateInvoiceOnce(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	order := testOrder("once-1")
+	order.Lines = []OrderLine{{LineNumber: 1, Description: "Plan", Quantity: 1, UnitNetPrice: MustParseAmount("100").Ptr()}}
+	first, created, err := client.CreateInvoiceOnce(order, ByReference)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !created {
+		t.Fatalf("Expected the first call to create the invoice")
+	}
+	again, created, err := client.CreateInvoiceOnce(order, ByReference)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if created || again.DraftInvoiceNumber != first.DraftInvoiceNumber {
+		t.Fatalf("Expected draft %d to be returned, got %+v (created %v)", first.DraftInvoiceNumber, again, created)
+	}
+
+	booked, err := client.BookInvoice(first.DraftInvoiceNumber)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if _, err := client.CreateCreditNoteForBookedInvoice(booked.BookedInvoiceNumber); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	again, created, err = client.CreateInvoiceOnce(order, ByReference)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if created || again.BookedInvoiceNumber != booked.BookedInvoiceNumber {
+		t.Fatalf("Expected booked invoice %d, not the credit note, got %+v", booked.BookedInvoiceNumber, again)
+	}
+	if n := countRequests(srv, http.MethodPost, "invoices/drafts"); n != 2 {
+		t.Fatalf("Expected one invoice and one credit note to be posted, got %d", n)
+	}
+
+	byId := testOrder("")
+	byId.ExternalId = "pipeline-42"
+	if _, created, err := client.CreateInvoiceOnce(byId, ByExternalId); err != nil || !created {
+		t.Fatalf("Expected the invoice to be created, got created %v, error %v", created, err)
+	}
+	if _, created, err := client.CreateInvoiceOnce(byId, ByExternalId); err != nil || created {
+		t.Fatalf("Expected the existing invoice, got created %v, error %v", created, err)
+	}
+	// re-running with another date still finds the invoice
+	byId.Date = byId.Date.AddDays(3)
+	if _, created, err := client.CreateInvoiceOnce(byId, ByExternalId); err != nil || created {
+		t.Fatalf("Expected the existing invoice for a changed date, got created %v, error %v", created, err)
+	}
+	order.Date = order.Date.AddDays(3)
+	if again, created, err := client.CreateInvoiceOnce(order, ByReference); err != nil || created || again.BookedInvoiceNumber != booked.BookedInvoiceNumber {
+		t.Fatalf("Expected booked invoice %d for a changed date, got %+v (created %v, error %v)", booked.BookedInvoiceNumber, again, created, err)
+	}
+	if _, _, err := client.CreateInvoiceOnce(testOrder(""), ByReference); err == nil {
+		t.Fatalf("Expected an error without a reference")
+	}
+}
@@ -0,0 +1,217 @@
+package economic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Sales orders move from "drafts" to "sent" when they are marked as sent, and
+// to "archived" once e-conomic has invoiced them.
+var orderClasses = []string{"drafts", "sent", "archived"}
+
+func IsValidOrderClass(class string) bool {
+	for _, c := range orderClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateOrderClass(class string) error {
+	if !IsValidOrderClass(class) {
+		return fmt.Errorf("invalid order class '%s'", class)
+	}
+	return nil
+}
+
+func (client *Client) GetOrders(class string) ([]Order, error) {
+	return client.GetOrdersContext(context.Background(), class)
+}
+
+func (client *Client) GetOrdersContext(ctx context.Context, class string) ([]Order, error) {
+	return client.OrdersPager(class, ListOptions{PageSize: MAX_PAGE_SIZE}).Collect(ctx)
+}
+
+// OrdersPager pages through the sales orders of a class ("drafts", "sent" or
+// "archived").
+func (client *Client) OrdersPager(class string, opts ListOptions) *Pager[Order] {
+	if err := ValidateOrderClass(class); err != nil {
+		return errPager[Order](err)
+	}
+	tc := &TypedClient[Order]{client: client}
+	return tc.pager("orders/"+class, opts)
+}
+
+func (client *Client) GetDraftOrder(orderNo int) (Order, error) {
+	return client.GetDraftOrderContext(context.Background(), orderNo)
+}
+
+func (client *Client) GetDraftOrderContext(ctx context.Context, orderNo int) (Order, error) {
+	return client.getOrder(ctx, "drafts", orderNo)
+}
+
+func (client *Client) GetSentOrder(orderNo int) (Order, error) {
+	return client.GetSentOrderContext(context.Background(), orderNo)
+}
+
+func (client *Client) GetSentOrderContext(ctx context.Context, orderNo int) (Order, error) {
+	return client.getOrder(ctx, "sent", orderNo)
+}
+
+func (client *Client) GetArchivedOrder(orderNo int) (Order, error) {
+	return client.GetArchivedOrderContext(context.Background(), orderNo)
+}
+
+func (client *Client) GetArchivedOrderContext(ctx context.Context, orderNo int) (Order, error) {
+	return client.getOrder(ctx, "archived", orderNo)
+}
+
+func (client *Client) getOrder(ctx context.Context, class string, orderNo int) (order Order, err error) {
+	err = client.callRestAPI(ctx, fmt.Sprintf("orders/%s/%d", class, orderNo), http.MethodGet, nil, &order)
+	return
+}
+
+func (client *Client) CreateOrder(order *Order) (Order, error) {
+	return client.CreateOrderContext(context.Background(), order)
+}
+
+// CreateOrderContext creates a draft sales order. Like CreateInvoice, it is
+// only retried after a 5xx or network error when order has references.other
+// or ExternalId to look it up by.
+func (client *Client) CreateOrderContext(ctx context.Context, order *Order) (created Order, err error) {
+	err = client.callRestAPI(ctx, "orders/drafts", http.MethodPost, order, &created, client.draftOrderLookup(order, &created))
+	return
+}
+
+// draftOrderLookup is draftInvoiceLookup for draft orders.
+func (client *Client) draftOrderLookup(order *Order, created *Order) lookupFunc {
+	ref := ""
+	if order.References != nil {
+		ref = order.References.Other
+	}
+	if ref == "" && order.ExternalId == "" {
+		return nil
+	}
+	return func(ctx context.Context) (bool, error) {
+		tc := &TypedClient[Order]{client: client}
+		drafts, err := tc.getEntities(ctx, "orders/drafts", ListOptions{PageSize: MAX_PAGE_SIZE, Filter: sameOrderFilter(order, ref)})
+		if err != nil {
+			return false, err
+		}
+		found := false
+		for _, draft := range drafts {
+			if order.ExternalId != "" && draft.ExternalId != order.ExternalId {
+				continue
+			}
+			if !found || draft.OrderNumber > created.OrderNumber {
+				*created = draft
+				found = true
+			}
+		}
+		return found, nil
+	}
+}
+
+func (client *Client) UpdateDraftOrder(order *Order) (Order, error) {
+	return client.UpdateDraftOrderContext(context.Background(), order)
+}
+
+// UpdateDraftOrderContext replaces the draft order with the same OrderNumber.
+// Sent orders must be moved back to drafts in e-conomic before they can be
+// changed.
+func (client *Client) UpdateDraftOrderContext(ctx context.Context, order *Order) (updated Order, err error) {
+	if order.OrderNumber == 0 {
+		return updated, fmt.Errorf("cannot update a draft order without an order number")
+	}
+	err = client.callRestAPI(ctx, fmt.Sprintf("orders/drafts/%d", order.OrderNumber), http.MethodPut, order, &updated)
+	return
+}
+
+func (client *Client) DeleteDraftOrder(orderNo int) error {
+	return client.DeleteDraftOrderContext(context.Background(), orderNo)
+}
+
+func (client *Client) DeleteDraftOrderContext(ctx context.Context, orderNo int) error {
+	return client.callRestAPI(ctx, fmt.Sprintf("orders/drafts/%d", orderNo), http.MethodDelete, nil, nil)
+}
+
+func (client *Client) MarkOrderAsSent(orderNo int) (Order, error) {
+	return client.MarkOrderAsSentContext(context.Background(), orderNo)
+}
+
+// MarkOrderAsSentContext moves a draft order to the sent orders. It does not
+// email the order; e-conomic only records that it was sent.
+func (client *Client) MarkOrderAsSentContext(ctx context.Context, orderNo int) (sent Order, err error) {
+	draft, err := client.GetDraftOrderContext(ctx, orderNo)
+	if err != nil {
+		return sent, err
+	}
+	err = client.callRestAPI(ctx, "orders/sent", http.MethodPost, draft, &sent, client.sentOrderLookup(orderNo, &sent))
+	return
+}
+
+// sentOrderLookup finds an order moved to sent by a failed MarkOrderAsSent.
+func (client *Client) sentOrderLookup(orderNo int, sent *Order) lookupFunc {
+	return func(ctx context.Context) (bool, error) {
+		order, err := client.GetSentOrderContext(ctx, orderNo)
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		*sent = order
+		return true, nil
+	}
+}
+
+func (client *Client) DownloadOrderPdf(class string, orderNo int, w io.Writer) error {
+	return client.DownloadOrderPdfContext(context.Background(), class, orderNo, w)
+}
+
+// DownloadOrderPdfContext streams the PDF of a sales order in class to w.
+func (client *Client) DownloadOrderPdfContext(ctx context.Context, class string, orderNo int, w io.Writer) error {
+	if err := ValidateOrderClass(class); err != nil {
+		return err
+	}
+	_, err := client.download(ctx, fmt.Sprintf("orders/%s/%d/pdf", class, orderNo), w)
+	return err
+}
+
+func (client *Client) ConvertOrderToInvoice(class string, orderNo int) (Invoice, error) {
+	return client.ConvertOrderToInvoiceContext(context.Background(), class, orderNo)
+}
+
+// ConvertOrderToInvoiceContext creates a draft invoice with the contents of
+// a draft or sent order. The order itself is left as it is. Set
+// references.other on the order so that a failed attempt can be retried
+// without creating a second invoice.
+func (client *Client) ConvertOrderToInvoiceContext(ctx context.Context, class string, orderNo int) (invoice Invoice, err error) {
+	if class != "drafts" && class != "sent" {
+		return invoice, fmt.Errorf("only draft and sent orders can be converted to an invoice, not '%s'", class)
+	}
+	order, err := client.getOrder(ctx, class, orderNo)
+	if err != nil {
+		return invoice, err
+	}
+	return client.CreateInvoiceContext(ctx, order.copyContents())
+}
+
+// copyContents returns a copy of an order or quote without the properties
+// that belong to the document itself, to create another document from it.
+func (o Order) copyContents() *Order {
+	o.OrderNumber = 0
+	o.Self = ""
+	o.Pdf = nil
+	o.Soap = nil
+	o.GrossAmount = nil
+	o.MarginInBaseCurrency = nil
+	o.MarginPercentage = nil
+	o.NetAmount = nil
+	o.RoundingAmount = nil
+	o.VatAmount = nil
+	return &o
+}
@@ -2,13 +2,49 @@ package economic
 
 import (
 	"os"
+	"testing"
+
+	"github.com/Opus-EDB/e-conomic/econtest"
 )
 
 // put shared test code here
 
-func getTestClient() *Client {
-	return &Client{
-		AgreementGrant: os.Getenv("ECONOMIC_AGREEMENT_GRANT_TOKEN"), // need a test account
-		AppSecretToken: os.Getenv("ECONOMIC_APP_SECRET_TOKEN"),
+// getTestClient returns a client for the agreement in
+// ECONOMIC_AGREEMENT_GRANT_TOKEN/ECONOMIC_APP_SECRET_TOKEN when both are set.
+// Otherwise it returns a client for a fresh econtest server seeded with the
+// reference data the tests expect, so the tests run offline by default.
+func getTestClient(t *testing.T) *Client {
+	grant := os.Getenv("ECONOMIC_AGREEMENT_GRANT_TOKEN") // need a test account
+	secret := os.Getenv("ECONOMIC_APP_SECRET_TOKEN")
+	if grant != "" && secret != "" {
+		return &Client{AgreementGrant: grant, AppSecretToken: secret}
 	}
+	client, _ := getOfflineTestClient(t)
+	return client
+}
+
+// getOfflineTestClient always uses a fresh econtest server, for tests that
+// seed or inspect its data.
+func getOfflineTestClient(t *testing.T) (*Client, *econtest.Server) {
+	srv := newTestServer(t)
+	return &Client{
+		AgreementGrant: econtest.AgreementGrant,
+		AppSecretToken: econtest.AppSecretToken,
+		RestBaseURL:    srv.RestURL(),
+		OpenAPIBaseURL: srv.OpenAPIURL(),
+	}, srv
+}
+
+func newTestServer(t *testing.T) *econtest.Server {
+	srv := econtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Add("payment-terms", PaymentTerm{PaymentTermsNumber: 10, Name: "Netto 8 dage", DaysOfCredit: 8, PaymentTermsType: "net"})
+	srv.Add("layouts", Layout{LayoutNumber: 19, Name: "Standard"})
+	srv.Add("vat-zones", VatZone{VatZoneNumber: 1, Name: "Domestic", EnabledForCustomer: true, EnabledForSupplier: true},
+		VatZone{VatZoneNumber: 2, Name: "EU", EnabledForCustomer: true, EnabledForSupplier: true})
+	srv.Add("units", Unit{UnitNumber: 1, Name: "stk."}, Unit{UnitNumber: 2, Name: "timer"})
+	srv.Add("currencies", Currency{Code: "DKK", IsoNumber: "208", Name: "Danske kroner"}, Currency{Code: "EUR", IsoNumber: "978", Name: "Euro"})
+	srv.Add("customer-groups", CustomerGroup{CustomerGroupNumber: 1, Name: "Indenlandske"})
+	srv.Add("products", Product{ProductNumber: "1"})
+	return srv
 }
@@ -0,0 +1,92 @@
+package economic
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttle paces requests to e-conomic with a token bucket and caps the
+// number of requests in flight. Retries count as requests too. Share one
+// Throttle between all clients and workers using the same agreement:
+//
+//	throttle := economic.NewThrottle(5, 10, 4)
+//	a := &economic.Client{..., Throttle: throttle}
+//	b := &economic.Client{..., Throttle: throttle}
+type Throttle struct {
+	rate  float64 // tokens per second; 0 means no rate limit
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	slots chan struct{} // nil means no concurrency limit
+}
+
+// NewThrottle allows requestsPerSecond on average with bursts of up to burst
+// requests, and at most maxInFlight concurrent requests. A zero
+// requestsPerSecond or maxInFlight disables that limit.
+func NewThrottle(requestsPerSecond float64, burst, maxInFlight int) *Throttle {
+	if burst < 1 {
+		burst = 1
+	}
+	t := &Throttle{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if maxInFlight > 0 {
+		t.slots = make(chan struct{}, maxInFlight)
+	}
+	return t
+}
+
+// acquire waits for a token and a free slot. The returned func gives the
+// slot back and must be called once the response has been read.
+func (t *Throttle) acquire(ctx context.Context) (release func(), err error) {
+	if t == nil {
+		return func() {}, nil
+	}
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+	if t.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case t.slots <- struct{}{}:
+		return func() { <-t.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait takes a token from the bucket, sleeping until one is available. The
+// token is reserved up front so concurrent callers queue up fairly.
+func (t *Throttle) wait(ctx context.Context) error {
+	if t.rate <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	now := time.Now()
+	t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	t.last = now
+	t.tokens--
+	var delay time.Duration
+	if t.tokens < 0 {
+		delay = time.Duration(-t.tokens / t.rate * float64(time.Second))
+	}
+	t.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		t.mu.Lock()
+		t.tokens++ // hand the reservation back
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottleRate(t *testing.T) {
+	throttle := NewThrottle(20, 1, 0)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := throttle.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Error: %s", err)
+		}
+		release()
+	}
+	// the first request uses the burst, the other 4 wait 50ms each
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Fatalf("Expected 5 requests at 20/s to take at least 200ms, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := NewThrottle(0.1, 1, 0)
+	if _, err := slow.acquire(ctx); err != nil {
+		t.Fatalf("Expected the burst to be available, got %s", err)
+	}
+	if _, err := slow.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestThrottleMaxInFlight(t *testing.T) {
+	var inFlight, maxSeen int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	throttle := NewThrottle(0, 0, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// separate clients sharing the throttle, like separate workers
+			client := &Client{AgreementGrant: "grant", AppSecretToken: "secret", RestBaseURL: server.URL, OpenAPIBaseURL: server.URL, Throttle: throttle}
+			resp := map[string]any{}
+			if err := client.callRestAPI(context.Background(), "self", http.MethodGet, nil, &resp); err != nil {
+				t.Errorf("Error: %s", err)
+			}
+			if err := client.callAPI(context.Background(), "/journalsapi", http.MethodGet, nil, nil, &resp); err != nil {
+				t.Errorf("Error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxSeen > 2 {
+		t.Fatalf("Expected at most 2 requests in flight, saw %d", maxSeen)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"Sat, 01 Jun 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Sat, 01 Jun 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, test := range tests {
+		d, ok := retryAfter(test.value, now)
+		if d != test.expected || ok != test.ok {
+			t.Errorf("Expected %s, %v for %q, got %s, %v", test.expected, test.ok, test.value, d, ok)
+		}
+	}
+
+	res := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"3"}}}
+	if d := backoffDelay(0, res); d != 3*time.Second {
+		t.Errorf("Expected Retry-After to be respected, got %s", d)
+	}
+	for attempt := 0; attempt < 4; attempt++ {
+		full := baseDelay * (1 << attempt)
+		if d := backoffDelay(attempt, nil); d < full/2 || d > full {
+			t.Errorf("Expected attempt %d to wait between %s and %s, got %s", attempt, full/2, full, d)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package economic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrVoucherUnbalanced is returned when the lines of a voucher do not net to
+// zero in base currency.
+var ErrVoucherUnbalanced = errors.New("voucher does not balance")
+
+// VoucherLine is one line of a Voucher. A line with a contra account balances
+// itself; the others must together net to zero.
+type VoucherLine struct {
+	AccountNumber       int
+	ContraAccountNumber int
+	Amount              Amount  // Debit AccountNumber with a positive amount, credit it with a negative.
+	Currency            string  // Defaults to the voucher's currency.
+	ExchangeRate        float64 // Base currency per 100 units of Currency; 0 for lines in base currency.
+	Text                string
+	VatCode             string
+	ContraVatCode       string
+	Dimensions          []DimensionValue // Added to the draft entry once it is created.
+}
+
+// DimensionValue selects the value Key of dimension DimensionNumber.
+type DimensionValue struct {
+	DimensionNumber int
+	Key             int
+}
+
+// Voucher collects the lines of one voucher so they can be checked to
+// balance and then posted together as draft entries.
+//
+//	v := economic.NewVoucher(1, 1042, economic.NewDate(2024, 3, 1), "DKK")
+//	v.Add(economic.VoucherLine{AccountNumber: 5820, Amount: economic.MustParseAmount("1250")})
+//	v.Add(economic.VoucherLine{AccountNumber: 1010, Amount: economic.MustParseAmount("-1000")})
+//	v.Add(economic.VoucherLine{AccountNumber: 8000, Amount: economic.MustParseAmount("-250")})
+//	entries, err := client.PostVoucher(v)
+type Voucher struct {
+	JournalNumber   int
+	VoucherNumber   int
+	EntryTypeNumber int
+	Date            Date
+	Currency        string // Currency of lines that do not set one.
+	Lines           []VoucherLine
+}
+
+func NewVoucher(journalNumber, voucherNumber int, date Date, currency string) *Voucher {
+	return &Voucher{
+		JournalNumber: journalNumber,
+		VoucherNumber: voucherNumber,
+		Date:          date,
+		Currency:      currency,
+	}
+}
+
+// Add appends lines and returns v, so calls can be chained.
+func (v *Voucher) Add(lines ...VoucherLine) *Voucher {
+	v.Lines = append(v.Lines, lines...)
+	return v
+}
+
+func (v *Voucher) lineCurrency(line VoucherLine) string {
+	if line.Currency != "" {
+		return line.Currency
+	}
+	return v.Currency
+}
+
+// baseAmount converts the amount of a line to base currency, rounded to øre/cents.
+func (v *Voucher) baseAmount(line VoucherLine) Amount {
+	if line.ExchangeRate == 0 {
+		return line.Amount.Round(v.lineCurrency(line))
+	}
+	return line.Amount.Mul(line.ExchangeRate / 100).Round("")
+}
+
+// Balance is the sum in base currency of the lines without a contra account.
+// It is zero for a voucher that balances.
+func (v *Voucher) Balance() Amount {
+	balance := Amount{}
+	for _, line := range v.Lines {
+		if line.ContraAccountNumber == 0 {
+			balance = balance.Add(v.baseAmount(line))
+		}
+	}
+	return balance
+}
+
+// Validate checks that the voucher can be posted: it has a journal, voucher
+// number, date and lines, every line has an account and an amount, and the
+// lines balance.
+func (v *Voucher) Validate() error {
+	var errs []error
+	if v.JournalNumber == 0 {
+		errs = append(errs, fmt.Errorf("voucher has no journal number"))
+	}
+	if v.VoucherNumber == 0 {
+		errs = append(errs, fmt.Errorf("voucher has no voucher number"))
+	}
+	if v.Date.IsZero() {
+		errs = append(errs, fmt.Errorf("voucher has no date"))
+	}
+	if len(v.Lines) == 0 {
+		errs = append(errs, fmt.Errorf("voucher has no lines"))
+	}
+	for i, line := range v.Lines {
+		if line.AccountNumber == 0 {
+			errs = append(errs, fmt.Errorf("line %d has no account", i+1))
+		}
+		if line.Amount.IsZero() {
+			errs = append(errs, fmt.Errorf("line %d has no amount", i+1))
+		}
+		if v.lineCurrency(line) == "" {
+			errs = append(errs, fmt.Errorf("line %d has no currency", i+1))
+		}
+	}
+	if balance := v.Balance(); !balance.IsZero() {
+		errs = append(errs, fmt.Errorf("%w: voucher %d is off by %s in base currency", ErrVoucherUnbalanced, v.VoucherNumber, balance))
+	}
+	return errors.Join(errs...)
+}
+
+// Entries returns the draft entries the voucher is posted as, one per line.
+func (v *Voucher) Entries() []JournalEntry {
+	entries := make([]JournalEntry, len(v.Lines))
+	for i, line := range v.Lines {
+		entries[i] = JournalEntry{
+			EntryTypeNumber:     v.EntryTypeNumber,
+			VoucherNumber:       v.VoucherNumber,
+			JournalNumber:       v.JournalNumber,
+			Date:                v.Date,
+			Amount:              line.Amount,
+			Currency:            v.lineCurrency(line),
+			AccountNumber:       line.AccountNumber,
+			ContraAccountNumber: line.ContraAccountNumber,
+			Text:                line.Text,
+			VatCode:             line.VatCode,
+			ContraVatCode:       line.ContraVatCode,
+			ExchangeRate:        line.ExchangeRate,
+		}
+	}
+	return entries
+}
+
+func (client *Client) PostVoucher(v *Voucher) ([]JournalEntry, error) {
+	return client.PostVoucherContext(context.Background(), v)
+}
+
+// PostVoucherContext validates the voucher and creates its lines as draft
+// entries, with their dimensions. The journals API has no transactions, so
+// if a line fails, the entries already created are deleted again and the
+// error says whether that succeeded. The entries are returned with their
+// EntryNumber set.
+func (client *Client) PostVoucherContext(ctx context.Context, v *Voucher) ([]JournalEntry, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	entries := v.Entries()
+	created := map[int]bool{}
+	for i := range entries {
+		err := client.postVoucherLine(ctx, &entries[i], v.Lines[i].Dimensions, created)
+		if err != nil {
+			err = fmt.Errorf("posting line %d of voucher %d: %w", i+1, v.VoucherNumber, err)
+			return nil, errors.Join(err, client.rollbackVoucher(ctx, entries[:i+1]))
+		}
+	}
+	return entries, nil
+}
+
+func (client *Client) postVoucherLine(ctx context.Context, entry *JournalEntry, dimensions []DimensionValue, created map[int]bool) error {
+	if err := client.createJournalEntry(ctx, entry, created); err != nil {
+		return err
+	}
+	created[entry.EntryNumber] = true
+	for _, d := range dimensions {
+		err := client.AddDimensionValueToDraftEntryContext(ctx, d.DimensionNumber, d.Key, entry.JournalNumber, entry.EntryNumber)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackVoucher deletes the entries that were created, even if ctx is
+// cancelled.
+func (client *Client) rollbackVoucher(ctx context.Context, entries []JournalEntry) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].EntryNumber == 0 {
+			continue
+		}
+		if err := client.DeleteJournalEntryContext(ctx, &entries[i]); err != nil && !IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("draft entry %d was not rolled back: %w", entries[i].EntryNumber, err))
+		}
+	}
+	return errors.Join(errs...)
+}
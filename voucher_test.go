@@ -0,0 +1,82 @@
+package economic
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func testVoucher() *Voucher {
+	v := NewVoucher(1, 1042, NewDate(2024, 3, 1), "DKK")
+	v.Add(VoucherLine{AccountNumber: 5820, Amount: MustParseAmount("1250"), Text: "Payment"})
+	v.Add(VoucherLine{AccountNumber: 1010, Amount: MustParseAmount("-1000"), VatCode: "U25", Dimensions: []DimensionValue{{DimensionNumber: 1, Key: 7}}})
+	v.Add(VoucherLine{AccountNumber: 8000, Amount: MustParseAmount("-250")})
+	return v
+}
+
+func TestVoucherBalance(t *testing.T) {
+	v := testVoucher()
+	if err := v.Validate(); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	// 100 EUR at 745.50 against 745.50 DKK
+	v.Add(VoucherLine{AccountNumber: 5830, Amount: MustParseAmount("100"), Currency: "EUR", ExchangeRate: 745.5})
+	v.Add(VoucherLine{AccountNumber: 5820, Amount: MustParseAmount("-745.5")})
+	// a line with a contra account balances itself
+	v.Add(VoucherLine{AccountNumber: 5820, ContraAccountNumber: 5830, Amount: MustParseAmount("10")})
+	if err := v.Validate(); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	v.Add(VoucherLine{AccountNumber: 8000, Amount: MustParseAmount("0.01")})
+	err := v.Validate()
+	if !errors.Is(err, ErrVoucherUnbalanced) {
+		t.Fatalf("Expected ErrVoucherUnbalanced, got %v", err)
+	}
+	if v.Balance() != MustParseAmount("0.01") {
+		t.Fatalf("Expected a balance of 0.01, got %s", v.Balance())
+	}
+}
+
+func TestPostVoucher(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	unbalanced := testVoucher().Add(VoucherLine{AccountNumber: 8000, Amount: MustParseAmount("-1")})
+	if _, err := client.PostVoucher(unbalanced); !errors.Is(err, ErrVoucherUnbalanced) {
+		t.Fatalf("Expected ErrVoucherUnbalanced, got %v", err)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Fatalf("Expected an unbalanced voucher not to be posted")
+	}
+
+	entries, err := client.PostVoucher(testVoucher())
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if len(entries) != 3 || entries[1].EntryNumber == 0 || entries[1].VoucherNumber != 1042 || entries[1].Currency != "DKK" {
+		t.Fatalf("Expected the three lines as entries, got %+v", entries)
+	}
+	dimensions := srv.Items("dimension-data")
+	if len(dimensions) != 1 || dimensions[0]["entryNumber"] != float64(entries[1].EntryNumber) {
+		t.Fatalf("Expected the dimension on the second entry, got %v", dimensions)
+	}
+	balance, err := client.GetJournalBalanceById(1042)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if !balance.IsZero() {
+		t.Fatalf("Expected the voucher to balance, got %s", balance)
+	}
+}
+
+func TestPostVoucherRollsBack(t *testing.T) {
+	client, srv := getOfflineTestClient(t)
+	srv.FailNext(http.MethodPost, "dimensionsapi", http.StatusBadRequest)
+	if _, err := client.PostVoucher(testVoucher()); !IsValidation(err) {
+		t.Fatalf("Expected the dimension error, got %v", err)
+	}
+	if drafts := srv.Items("draft-entries"); len(drafts) != 0 {
+		t.Fatalf("Expected the created entries to be deleted, got %v", drafts)
+	}
+	if n := countRequests(srv, http.MethodPost, "journalsapi/v14.0.1/draft-entries"); n != 2 {
+		t.Fatalf("Expected the voucher to stop at the failing line, got %d entries posted", n)
+	}
+}